@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/storage"
+)
+
+func adminCommand(args []string) error {
+	return dispatch([]command{
+		{name: "grant-role", run: adminGrantRole},
+	}, args)
+}
+
+func adminGrantRole(args []string) error {
+	var steamID, role string
+
+	if _, err := flags("admin grant-role", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&steamID, "steamid", "", "SteamID64 of the player")
+		fs.StringVar(&role, "role", "", "root, admin or moderator")
+	}); err != nil {
+		return err
+	}
+
+	if steamID == "" {
+		return errors.New("admin grant-role: -steamid is required")
+	}
+
+	if !m.Role(role).Valid() {
+		return fmt.Errorf("admin grant-role: unknown role %q", role)
+	}
+
+	ctx := context.Background()
+
+	_, db, err := setup(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := storage.NewAdminStorage(db).SetRole(ctx, steamID, m.Role(role)); err != nil {
+		return fmt.Errorf("admin: %v", err)
+	}
+
+	fmt.Printf("%s is now %s\n", steamID, role)
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cs2-server/backend/config"
+	"github.com/cs2-server/backend/internal/storage"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+// command is a subcommand of the binary. Commands with subcommands dispatch
+// to them from run.
+type command struct {
+	name  string
+	usage string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{"serve", "serve the API (the default)", func([]string) error { return run() }},
+	{"migrate", "migrate [up | down [-steps n] | version]", migrateCommand},
+	{"token", "token issue -steamid id | token inspect token", tokenCommand},
+	{"admin", "admin grant-role -steamid id -role root|admin|moderator", adminCommand},
+	{"ban", "ban add -steamid id -reason text [-type ban|mute|gag] [-ip ip] [-duration 1h] | ban remove -id n -reason text", banCommand},
+	{"config", "config print [-redacted] | config check", configCommand},
+}
+
+var errUsage = errors.New("usage")
+
+func main() {
+	args := os.Args[1:]
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	if err := dispatch(commands, args); err != nil {
+		if errors.Is(err, errUsage) {
+			printUsage()
+			os.Exit(2)
+		}
+
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func dispatch(cmds []command, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	for _, c := range cmds {
+		if c.name == args[0] {
+			return c.run(args[1:])
+		}
+	}
+
+	return fmt.Errorf("unknown command %q: %w", args[0], errUsage)
+}
+
+func printUsage() {
+	var b strings.Builder
+
+	b.WriteString("usage:\n")
+
+	for _, c := range commands {
+		fmt.Fprintf(&b, "  %-8s %s\n", c.name, c.usage)
+	}
+
+	b.WriteString("\nconfig is read from the environment and the file named by CONFIG_FILE.\n")
+
+	fmt.Fprint(os.Stderr, b.String())
+}
+
+// flags parses args for the named subcommand. Usage errors are reported as
+// errUsage after flag has printed them.
+func flags(name string, args []string, define func(fs *flag.FlagSet)) (*flag.FlagSet, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	define(fs)
+
+	if err := fs.Parse(args); err != nil {
+		return nil, errUsage
+	}
+
+	return fs, nil
+}
+
+// connect opens the pool the server and the commands share.
+func connect(ctx context.Context, cfg *config.Config) (*pgxpool.Pool, error) {
+	db, err := storage.NewPool(cfg.Postgres)
+	if err != nil {
+		return nil, fmt.Errorf("db: %v", err)
+	}
+
+	if err := storage.Wait(ctx, db, cfg.Postgres, logrus.StandardLogger()); err != nil {
+		db.Close()
+
+		return nil, fmt.Errorf("db: %v", err)
+	}
+
+	return db, nil
+}
+
+// setup loads the config and connects to the database, as commands working
+// on the data need.
+func setup(ctx context.Context) (*config.Config, *pgxpool.Pool, error) {
+	cfg, err := config.Init()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cfg: %v", err)
+	}
+
+	db, err := connect(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return cfg, db, nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/cs2-server/backend/config"
+)
+
+func configCommand(args []string) error {
+	return dispatch([]command{
+		{name: "print", run: configPrint},
+		{name: "check", run: configCheck},
+	}, args)
+}
+
+// configPrint prints the config as loaded and then reports whether it is
+// valid.
+func configPrint(args []string) error {
+	var redact bool
+
+	if _, err := flags("config print", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&redact, "redacted", false, "hide secrets such as JWT_KEY")
+	}); err != nil {
+		return err
+	}
+
+	cfg, err := config.Read(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		return fmt.Errorf("cfg: %v", err)
+	}
+
+	if err := config.Print(os.Stdout, cfg, redact); err != nil {
+		return err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config:\n%v", err)
+	}
+
+	return nil
+}
+
+func configCheck([]string) error {
+	cfg, err := config.Read(os.Getenv("CONFIG_FILE"))
+	if err != nil {
+		return fmt.Errorf("cfg: %v", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config:\n%v", err)
+	}
+
+	fmt.Println("config is valid")
+
+	return nil
+}
@@ -3,13 +3,18 @@
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
 
 package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -19,84 +24,407 @@ import (
 	"github.com/cs2-server/backend/config"
 	_ "github.com/cs2-server/backend/docs"
 	"github.com/cs2-server/backend/internal/api"
+	"github.com/cs2-server/backend/internal/app"
+	"github.com/cs2-server/backend/internal/breaker"
+	"github.com/cs2-server/backend/internal/certs"
+	"github.com/cs2-server/backend/internal/events"
+	"github.com/cs2-server/backend/internal/metrics"
 	"github.com/cs2-server/backend/internal/middleware"
+	"github.com/cs2-server/backend/internal/ratelimit"
+	"github.com/cs2-server/backend/internal/router"
 	"github.com/cs2-server/backend/internal/service"
 	"github.com/cs2-server/backend/internal/storage"
+	"github.com/cs2-server/backend/internal/tracing"
+	"github.com/cs2-server/backend/migrations"
+	"github.com/cs2-server/backend/pkg/a2s"
 	"github.com/cs2-server/backend/pkg/jwt"
 	_ "github.com/go-sql-driver/mysql"
-	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/sirupsen/logrus"
 	swagger "github.com/swaggo/http-swagger"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
-func main() {
-	if err := run(); err != nil {
-		logrus.Fatalln(err)
-	}
-}
-
+// run serves the API until SIGTERM or SIGINT.
 func run() error {
 	logger := logrus.New()
+	logger.AddHook(middleware.RequestIDHook{})
+
+	// Packages that log through the standard logger get request IDs too.
+	logrus.AddHook(middleware.RequestIDHook{})
 
 	cfg, err := config.Init()
 	if err != nil {
 		return fmt.Errorf("cfg: %v", err)
 	}
 
-	db, err := pgxpool.Connect(context.Background(), cfg.Postgres.DSN)
+	setLogLevel(logger, cfg.Log.Level)
+
+	// Components are appended in start order and stopped in reverse: the
+	// server stops taking requests first, the pool closes and traces are
+	// flushed last.
+	a := app.New(logger, cfg.HTTP.ShutdownTimeout)
+
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("tracing: %v", err)
+	}
+
+	a.Append(app.Hook{Name: "tracing", Stop: shutdownTracing})
+
+	db, err := storage.NewPool(cfg.Postgres)
 	if err != nil {
 		return fmt.Errorf("db: %v", err)
 	}
 
-	jwt := jwt.New(cfg.JWT.Key)
-	auth := api.NewAuthAPI(cfg, logger, jwt, service.NewAuthService(storage.NewAuthStorage(db)))
+	a.Append(app.Hook{
+		Name: "postgres",
+		Start: func(ctx context.Context) error {
+			return storage.Wait(ctx, db, cfg.Postgres, logger)
+		},
+		Stop: func(context.Context) error {
+			db.Close()
+
+			return nil
+		},
+	})
+
+	metrics := metrics.New()
+	metrics.RegisterPool(db)
 
-	mux := http.NewServeMux()
-	corsMux := middleware.CORS(mux)
+	profileBreaker := breaker.New(cfg.Postgres.BreakerFailures, cfg.Postgres.BreakerCooldown)
+	authStorage := storage.NewAuthStorage(db, cfg.Postgres.QueryTimeout, profileBreaker)
 
-	mux.HandleFunc("GET /api/swagger/*", swagger.Handler(swagger.URL(cfg.Swagger.URL)))
+	jwt := jwt.New(cfg.JWT.Key, cfg.JWT.PreviousKeys, metrics)
+	auth := api.NewAuthAPI(cfg, logger, jwt, service.NewAuthService(authStorage, cfg.Steam.APIURL, metrics))
 
-	mux.HandleFunc("GET /api/auth/login", middleware.Log(auth.Login))
-	mux.HandleFunc("GET /api/auth/process", middleware.Log(auth.ProcessLogin))
-	mux.HandleFunc("POST /api/auth/refresh", jwt.Auth(middleware.Log(auth.RefreshToken)))
+	hub := events.NewHub(cfg.Events.Buffer)
 
-	mux.HandleFunc("GET /api/profile/{id}", jwt.Auth(middleware.Log(auth.GetProfile)))
+	a.Append(app.Hook{
+		Name: "events",
+		Stop: func(context.Context) error {
+			hub.Close()
+
+			return nil
+		},
+	})
+
+	adminStorage := storage.NewAdminStorage(db)
+	serverStorage := storage.NewServerStorage(db)
+
+	permissions := middleware.NewPermissions(service.NewAdminService(adminStorage), logger)
+
+	serverService := service.NewServerService(serverStorage, a2s.NewClient(cfg.Servers.QueryTimeout), hub, logger)
+	servers := api.NewServerAPI(logger, serverService)
+
+	rconService := service.NewRCONService(serverStorage, adminStorage, storage.NewRCONStorage(db), cfg.RCON.Timeout, logger)
+
+	a.Append(app.Hook{
+		Name: "rcon",
+		Stop: func(context.Context) error {
+			rconService.Close()
+
+			return nil
+		},
+	})
+
+	rcon := api.NewRCONAPI(logger, rconService)
+
+	apiKeyService := service.NewAPIKeyService(storage.NewAPIKeyStorage(db), serverStorage)
+	apiKeys := middleware.NewAPIKeys(apiKeyService, logger)
+
+	tlsConfig, certReloader, err := serverTLS(cfg.TLS, logger)
+	if err != nil {
+		return fmt.Errorf("tls: %v", err)
+	}
+
+	if cfg.TLS.ClientCAFile != "" {
+		apiKeys.RequireClientCert()
+	}
+	keys := api.NewAPIKeyAPI(logger, apiKeyService)
+
+	bans := api.NewBanAPI(logger, service.NewBanService(storage.NewBanStorage(db), hub))
+	cors, err := middleware.NewCORSPolicy(cfg.CORS)
+	if err != nil {
+		return fmt.Errorf("cors: %v", err)
+	}
+
+	eventStream := api.NewEventAPI(logger, hub, cfg.Events.Heartbeat, cors.AllowOrigin)
+
+	latestMigration, err := migrations.Latest()
+	if err != nil {
+		return fmt.Errorf("migrations: %v", err)
+	}
+
+	var steamHealthURL string
+	if cfg.Health.SteamCheck {
+		steamHealthURL = cfg.Steam.APIURL + service.SteamHealthPath
+	}
+
+	healthService := service.NewHealthService(storage.NewHealthStorage(db), latestMigration, steamHealthURL, cfg.Health.SteamInterval)
+	health := api.NewHealthAPI(logger, healthService)
+
+	limits, err := rateLimits(cfg.RateLimit)
+	if err != nil {
+		return fmt.Errorf("rate limit: %v", err)
+	}
+
+	var store ratelimit.Store
+	switch cfg.RateLimit.Backend {
+	case "memory":
+		store = ratelimit.NewMemoryStore()
+	case "postgres":
+		pgStore := ratelimit.NewPostgresStore(db, logger)
+		store = pgStore
+
+		a.Go("rate limit cleanup", func(ctx context.Context) {
+			pgStore.Run(ctx, time.Minute)
+		})
+	default:
+		return fmt.Errorf("rate limit: unknown backend %q", cfg.RateLimit.Backend)
+	}
+
+	limiter := middleware.NewRateLimiter(store, limits, logger)
+	accessLog := middleware.NewAccessLog(logger)
+
+	proxies, err := middleware.NewTrustedProxies(cfg.HTTP.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("trusted proxies: %v", err)
+	}
+
+	maps := api.NewMapAPI(logger, service.NewMapService(storage.NewMapStorage(db), hub))
+
+	handlers := api.Handlers{
+		Auth:    auth,
+		Servers: servers,
+		RCON:    rcon,
+		APIKeys: keys,
+		Bans:    bans,
+		Events:  eventStream,
+		Maps:    maps,
+	}
+
+	guards := api.Guards{
+		JWT:          jwt,
+		Permissions:  permissions,
+		APIKeys:      apiKeys,
+		AccessLog:    accessLog,
+		LoginLimit:   limiter.Limit("login"),
+		RefreshLimit: limiter.Limit("refresh"),
+		ProfileLimit: limiter.Limit("profile"),
+		AdminLimit:   limiter.Limit("admin"),
+	}
+
+	routes := router.New()
+
+	routes.Handle(http.MethodGet, "/api/swagger/", swagger.Handler(swagger.URL(cfg.Swagger.URL)))
+	routes.Handle(http.MethodGet, "/healthz", http.HandlerFunc(health.Healthz))
+	routes.Handle(http.MethodGet, "/readyz", http.HandlerFunc(health.Readyz))
+
+	api.Register(routes.Group(api.V1.Prefix(), api.V1.Handler), handlers, guards)
+
+	// The unversioned paths stay as deprecated aliases of v1.
+	legacy := middleware.Deprecated(cfg.API.LegacyDeprecated, cfg.API.LegacySunset, "/api", api.V1.Prefix())
+	api.Register(routes.Group("/api", api.V1.Handler, legacy).Deprecate(), handlers, guards)
+
+	handler := middleware.Recover(logger, metrics,
+		proxies.Handler(
+			accessLog.Handler(
+				middleware.Trace(routes,
+					middleware.Metrics(metrics, routes,
+						middleware.Compress(
+							cors.Handler(routes)))))))
+
+	a.Go("server poller", func(ctx context.Context) {
+		serverService.Run(ctx, cfg.Servers.PollInterval)
+	})
+
+	reloader := &reloader{
+		logger:  logger,
+		cors:    cors,
+		limiter: limiter,
+		auth:    auth,
+		jwt:     jwt,
+		current: cfg,
+	}
+
+	s := &http.Server{
+		Addr:              cfg.HTTP.Host + ":" + cfg.HTTP.Port,
+		Handler:           handler,
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+		MaxHeaderBytes:    cfg.HTTP.MaxHeaderBytes,
+		TLSConfig:         tlsConfig,
+	}
+
+	if cfg.HTTP.H2C {
+		s.Handler = h2c.NewHandler(handler, &http2.Server{IdleTimeout: cfg.HTTP.IdleTimeout})
+	}
+
+	// Ends event streams, which Shutdown would otherwise wait on forever.
+	s.RegisterOnShutdown(hub.Close)
+
+	if certReloader != nil {
+		a.Go("tls reloader", func(ctx context.Context) {
+			certReloader.Run(ctx, cfg.TLS.ReloadInterval)
+		})
+	}
+
+	// Metrics stay off the public listener and outlive it on shutdown, so the
+	// drain can still be observed.
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("GET /metrics", metrics.Handler())
+
+	ms := &http.Server{
+		Addr:              cfg.Metrics.Addr,
+		Handler:           metricsMux,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+	}
+
+	a.Append(app.Hook{
+		Name: "metrics",
+		Start: func(context.Context) error {
+			ln, err := net.Listen("tcp", ms.Addr)
+			if err != nil {
+				return err
+			}
+
+			go func() {
+				if err := ms.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					a.Fail(fmt.Errorf("metrics: %v", err))
+				}
+			}()
+
+			logger.Infof("metrics are served on %s", ln.Addr())
+
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			return ms.Shutdown(ctx)
+		},
+	})
+
+	a.Append(app.Hook{
+		Name: "http",
+		Start: func(context.Context) error {
+			ln, err := net.Listen("tcp", s.Addr)
+			if err != nil {
+				return err
+			}
+
+			serve, scheme := s.Serve, "http"
+			if tlsConfig != nil {
+				// The certificate comes from TLSConfig. HTTP/2 is negotiated by ALPN.
+				serve, scheme = func(ln net.Listener) error { return s.ServeTLS(ln, "", "") }, "https"
+			}
+
+			go func() {
+				if err := serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					a.Fail(fmt.Errorf("http: %v", err))
+				}
+			}()
+
+			logger.Infof("%s server is listening on %s", scheme, ln.Addr())
+
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			// Fail readiness first so load balancers stop routing new
+			// requests here while the ones in flight still complete.
+			logger.Infof("draining for %s...", cfg.HTTP.DrainDelay)
+			healthService.Drain()
+
+			select {
+			case <-time.After(cfg.HTTP.DrainDelay):
+			case <-ctx.Done():
+			}
+
+			logger.Infoln("shutting down...")
+
+			return s.Shutdown(ctx)
+		},
+	})
 
 	var (
 		sigCh = make(chan os.Signal, 1)
-		errCh = make(chan error)
+		hupCh = make(chan os.Signal, 1)
 	)
 
 	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	signal.Notify(hupCh, syscall.SIGHUP)
 
-	s := &http.Server{
-		Addr:         cfg.HTTP.Host + ":" + cfg.HTTP.Port,
-		Handler:      corsMux,
-		ReadTimeout:  cfg.HTTP.ReadTimeout,
-		WriteTimeout: cfg.HTTP.WriteTimeout,
+	if err := a.Start(context.Background()); err != nil {
+		return err
 	}
 
-	go func() {
-		if err := s.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			errCh <- fmt.Errorf("http start: %v", err)
+	for {
+		select {
+		case <-hupCh:
+			if err := reloader.reload(); err != nil {
+				logger.Errorln("reload:", err)
+			}
+		case sig := <-sigCh:
+			logger.Infof("got signal: %s", sig)
+
+			return a.Stop()
+		case err := <-a.Failed():
+			return errors.Join(err, a.Stop())
 		}
-	}()
+	}
+}
 
-	logger.Infof("http server is listening on %s:%s", cfg.HTTP.Host, cfg.HTTP.Port)
+// serverTLS returns the TLS config serving the reloadable certificate and,
+// with a client CA, verifying the client certificates game servers present.
+// Both are nil when TLS is disabled.
+func serverTLS(cfg config.TLS, logger *logrus.Logger) (*tls.Config, *certs.Reloader, error) {
+	if !cfg.Enabled() {
+		return nil, nil, nil
+	}
 
-	select {
-	case sig := <-sigCh:
-		logger.Infof("got signal: %s. shutting down...", sig)
+	reloader, err := certs.NewReloader(cfg.CertFile, cfg.KeyFile, logger)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
 
-		if err := s.Shutdown(ctx); err != nil {
-			return fmt.Errorf("http shutdown: %v", err)
+	if cfg.ClientCAFile != "" {
+		pool, err := certs.ClientCAs(cfg.ClientCAFile)
+		if err != nil {
+			return nil, nil, err
 		}
-	case err := <-errCh:
-		return err
+
+		// Players connect without a certificate, so it is required per route
+		// by APIKeys rather than by the handshake.
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	return tlsConfig, reloader, nil
+}
+
+// rateLimits maps the route groups passed to RateLimiter.Limit to their rate.
+func rateLimits(cfg config.RateLimit) (map[string]ratelimit.Rate, error) {
+	limits := make(map[string]ratelimit.Rate)
+
+	for group, s := range map[string]string{
+		"login":   cfg.Login,
+		"refresh": cfg.Refresh,
+		"profile": cfg.Profile,
+		"admin":   cfg.Admin,
+	} {
+		rate, err := ratelimit.ParseRate(s)
+		if err != nil {
+			return nil, err
+		}
+
+		limits[group] = rate
 	}
 
-	return nil
+	return limits, nil
 }
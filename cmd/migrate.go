@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/cs2-server/backend/internal/storage"
+	"github.com/cs2-server/backend/migrations"
+)
+
+func migrateCommand(args []string) error {
+	if len(args) == 0 {
+		args = []string{"up"}
+	}
+
+	return dispatch([]command{
+		{name: "up", run: migrateUp},
+		{name: "down", run: migrateDown},
+		{name: "version", run: migrateVersion},
+	}, args)
+}
+
+func migrateUp([]string) error {
+	ctx := context.Background()
+
+	_, db, err := setup(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	applied, err := migrations.Up(ctx, db)
+	for _, v := range applied {
+		fmt.Printf("applied %d\n", v)
+	}
+
+	if err != nil {
+		return fmt.Errorf("migrate: %v", err)
+	}
+
+	if len(applied) == 0 {
+		fmt.Println("schema is up to date")
+	}
+
+	return nil
+}
+
+func migrateDown(args []string) error {
+	var steps int
+
+	if _, err := flags("migrate down", args, func(fs *flag.FlagSet) {
+		fs.IntVar(&steps, "steps", 1, "number of migrations to revert")
+	}); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	_, db, err := setup(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	reverted, err := migrations.Down(ctx, db, steps)
+	for _, v := range reverted {
+		fmt.Printf("reverted %d\n", v)
+	}
+
+	if err != nil {
+		return fmt.Errorf("migrate: %v", err)
+	}
+
+	return nil
+}
+
+func migrateVersion([]string) error {
+	ctx := context.Background()
+
+	_, db, err := setup(ctx)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	latest, err := migrations.Latest()
+	if err != nil {
+		return fmt.Errorf("migrations: %v", err)
+	}
+
+	version, dirty, err := storage.NewHealthStorage(db).MigrationVersion(ctx)
+	if err != nil && !errors.Is(err, storage.ErrNoMigrations) {
+		return fmt.Errorf("migrate: %v", err)
+	}
+
+	fmt.Printf("version %d of %d", version, latest)
+	if dirty {
+		fmt.Print(", dirty")
+	}
+	fmt.Println()
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cs2-server/backend/config"
+	"github.com/cs2-server/backend/internal/api"
+	"github.com/cs2-server/backend/internal/middleware"
+	"github.com/cs2-server/backend/pkg/jwt"
+	"github.com/sirupsen/logrus"
+)
+
+// reloader applies the settings tagged reload in config.Config to the
+// running server, on SIGHUP.
+type reloader struct {
+	logger  *logrus.Logger
+	cors    *middleware.CORSPolicy
+	limiter *middleware.RateLimiter
+	auth    *api.AuthAPI
+	jwt     *jwt.JWT
+	// current is the config the process runs with.
+	current *config.Config
+}
+
+// reload reads and validates the config again and swaps in the new
+// settings. Nothing is applied if the config is invalid. Requests in flight
+// finish with the settings they started with.
+func (r *reloader) reload() error {
+	next, err := config.Init()
+	if err != nil {
+		return fmt.Errorf("reload (1): %w", err)
+	}
+
+	limits, err := rateLimits(next.RateLimit)
+	if err != nil {
+		return fmt.Errorf("reload (2): %w", err)
+	}
+
+	// The only step that can fail goes first, so a config is applied whole
+	// or not at all.
+	if err := r.cors.Reload(next.CORS); err != nil {
+		return fmt.Errorf("reload (3): %w", err)
+	}
+
+	setLogLevel(r.logger, next.Log.Level)
+	r.limiter.SetRates(limits)
+	r.auth.SetSteamAPIKey(next.Steam.APIKey)
+	r.jwt.SetKeys(next.JWT.Key, next.JWT.PreviousKeys)
+
+	changes := config.Diff(r.current, next)
+	for _, c := range changes {
+		entry := r.logger.WithFields(logrus.Fields{"setting": c.Env, "from": c.From, "to": c.To})
+
+		if c.Reload {
+			entry.Infoln("config reloaded")
+		} else {
+			entry.Warnln("config change needs a restart")
+		}
+	}
+
+	if len(changes) == 0 {
+		r.logger.Infoln("config reloaded, nothing changed")
+	}
+
+	// Settings that need a restart keep their running value, so they are
+	// reported again until the process is restarted.
+	r.current = config.Reloaded(r.current, next)
+
+	return nil
+}
+
+// setLogLevel sets the level of logger and of the standard logger, which
+// some packages log through. level has been validated by config.
+func setLogLevel(logger *logrus.Logger, level string) {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		return
+	}
+
+	logger.SetLevel(lvl)
+	logrus.SetLevel(lvl)
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/cs2-server/backend/config"
+	"github.com/cs2-server/backend/pkg/jwt"
+)
+
+func tokenCommand(args []string) error {
+	return dispatch([]command{
+		{name: "issue", run: tokenIssue},
+		{name: "inspect", run: tokenInspect},
+	}, args)
+}
+
+// tokenIssue signs tokens for a player without going through Steam, for
+// testing.
+func tokenIssue(args []string) error {
+	var steamID string
+
+	if _, err := flags("token issue", args, func(fs *flag.FlagSet) {
+		fs.StringVar(&steamID, "steamid", "", "SteamID64 of the player")
+	}); err != nil {
+		return err
+	}
+
+	if steamID == "" {
+		return errors.New("token issue: -steamid is required")
+	}
+
+	cfg, err := config.Init()
+	if err != nil {
+		return fmt.Errorf("cfg: %v", err)
+	}
+
+	tokens, err := jwt.New(cfg.JWT.Key, cfg.JWT.PreviousKeys, nil).GenerateTokens(steamID)
+	if err != nil {
+		return fmt.Errorf("token: %v", err)
+	}
+
+	return printJSON(tokens)
+}
+
+// tokenInspect prints the claims of a token and whether it is valid with the
+// configured keys.
+func tokenInspect(args []string) error {
+	fs, err := flags("token inspect", args, func(*flag.FlagSet) {})
+	if err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return errUsage
+	}
+
+	cfg, err := config.Init()
+	if err != nil {
+		return fmt.Errorf("cfg: %v", err)
+	}
+
+	claims, verifyErr := jwt.New(cfg.JWT.Key, cfg.JWT.PreviousKeys, nil).Inspect(fs.Arg(0))
+	if claims == nil {
+		return fmt.Errorf("token: %v", verifyErr)
+	}
+
+	out := struct {
+		ID        string    `json:"id"`
+		ExpiresAt time.Time `json:"expires_at"`
+		Valid     bool      `json:"valid"`
+		Error     string    `json:"error,omitempty"`
+	}{
+		ID:        claims.ID,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+		Valid:     verifyErr == nil,
+	}
+
+	if verifyErr != nil {
+		out.Error = verifyErr.Error()
+	}
+
+	return printJSON(out)
+}
@@ -2,47 +2,200 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
+// Config settings are named by their environment variable. Those tagged
+// reload are applied on SIGHUP, the rest take a restart.
 type Config struct {
-	HTTP     HTTP
-	Postgres Postgres
-	JWT      JWT
-	Steam    Steam
-	Swagger  Swagger
+	Log       Log       `yaml:"log" toml:"log"`
+	HTTP      HTTP      `yaml:"http" toml:"http"`
+	TLS       TLS       `yaml:"tls" toml:"tls"`
+	Postgres  Postgres  `yaml:"postgres" toml:"postgres"`
+	JWT       JWT       `yaml:"jwt" toml:"jwt"`
+	Steam     Steam     `yaml:"steam" toml:"steam"`
+	Swagger   Swagger   `yaml:"swagger" toml:"swagger"`
+	Servers   Servers   `yaml:"servers" toml:"servers"`
+	RCON      RCON      `yaml:"rcon" toml:"rcon"`
+	Events    Events    `yaml:"events" toml:"events"`
+	RateLimit RateLimit `yaml:"rate_limit" toml:"rate_limit"`
+	CORS      CORS      `yaml:"cors" toml:"cors"`
+	Metrics   Metrics   `yaml:"metrics" toml:"metrics"`
+	Tracing   Tracing   `yaml:"tracing" toml:"tracing"`
+	Health    Health    `yaml:"health" toml:"health"`
+	API       API       `yaml:"api" toml:"api"`
+}
+
+type Log struct {
+	Level string `yaml:"level" toml:"level" env:"LOG_LEVEL" env-default:"info" reload:"true"`
 }
 
 type HTTP struct {
-	Host         string        `env:"HTTP_HOST"`
-	Port         string        `env:"HTTP_PORT"`
-	ReadTimeout  time.Duration `env:"HTTP_READ_TIMEOUT"`
-	WriteTimeout time.Duration `env:"HTTP_WRITE_TIMEOUT"`
+	Host         string        `yaml:"host" toml:"host" env:"HTTP_HOST"`
+	Port         string        `yaml:"port" toml:"port" env:"HTTP_PORT" env-default:"4000"`
+	ReadTimeout  time.Duration `yaml:"read_timeout" toml:"read_timeout" env:"HTTP_READ_TIMEOUT" env-default:"15s"`
+	WriteTimeout time.Duration `yaml:"write_timeout" toml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" env-default:"15s"`
+	// DrainDelay is how long /readyz fails before shutdown starts.
+	DrainDelay time.Duration `yaml:"drain_delay" toml:"drain_delay" env:"HTTP_DRAIN_DELAY" env-default:"5s"`
+	// ShutdownTimeout bounds the whole shutdown, DrainDelay included.
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"HTTP_SHUTDOWN_TIMEOUT" env-default:"15s"`
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout" toml:"read_header_timeout" env:"HTTP_READ_HEADER_TIMEOUT" env-default:"5s"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout" toml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" env-default:"2m"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes" toml:"max_header_bytes" env:"HTTP_MAX_HEADER_BYTES" env-default:"1048576"`
+	// TrustedProxies are the CIDRs of the proxies whose Forwarded and
+	// X-Forwarded-* headers are believed.
+	TrustedProxies []string `yaml:"trusted_proxies" toml:"trusted_proxies" env:"HTTP_TRUSTED_PROXIES"`
+	// H2C serves HTTP/2 without TLS, for proxies that speak it to backends.
+	H2C bool `yaml:"h2c" toml:"h2c" env:"HTTP_H2C" env-default:"false"`
 }
 
+// TLS is served when a certificate is set, which is reloaded when its files
+// change. With ClientCAFile, game servers must also present a client
+// certificate signed by one of its CAs along with their API key.
+type TLS struct {
+	CertFile       string        `yaml:"cert_file" toml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile        string        `yaml:"key_file" toml:"key_file" env:"TLS_KEY_FILE"`
+	ClientCAFile   string        `yaml:"client_ca_file" toml:"client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+	ReloadInterval time.Duration `yaml:"reload_interval" toml:"reload_interval" env:"TLS_RELOAD_INTERVAL" env-default:"1m"`
+}
+
+func (t TLS) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// Postgres connections are retried ConnectAttempts times at startup, waiting
+// ConnectBackoff and then twice as long each time. Profile stats are served
+// without the database after BreakerFailures failed queries in a row, until
+// BreakerCooldown has passed.
 type Postgres struct {
-	DSN string `env:"PG_DSN"`
+	DSN               string        `yaml:"dsn" toml:"dsn" env:"PG_DSN" secret:"true"`
+	MaxConns          int           `yaml:"max_conns" toml:"max_conns" env:"PG_MAX_CONNS" env-default:"10"`
+	MinConns          int           `yaml:"min_conns" toml:"min_conns" env:"PG_MIN_CONNS" env-default:"0"`
+	MaxConnLifetime   time.Duration `yaml:"max_conn_lifetime" toml:"max_conn_lifetime" env:"PG_MAX_CONN_LIFETIME" env-default:"1h"`
+	MaxConnIdleTime   time.Duration `yaml:"max_conn_idle_time" toml:"max_conn_idle_time" env:"PG_MAX_CONN_IDLE_TIME" env-default:"30m"`
+	HealthCheckPeriod time.Duration `yaml:"health_check_period" toml:"health_check_period" env:"PG_HEALTH_CHECK_PERIOD" env-default:"1m"`
+	StatementTimeout  time.Duration `yaml:"statement_timeout" toml:"statement_timeout" env:"PG_STATEMENT_TIMEOUT" env-default:"10s"`
+	QueryTimeout      time.Duration `yaml:"query_timeout" toml:"query_timeout" env:"PG_QUERY_TIMEOUT" env-default:"2s"`
+	ConnectAttempts   int           `yaml:"connect_attempts" toml:"connect_attempts" env:"PG_CONNECT_ATTEMPTS" env-default:"10"`
+	ConnectBackoff    time.Duration `yaml:"connect_backoff" toml:"connect_backoff" env:"PG_CONNECT_BACKOFF" env-default:"500ms"`
+	BreakerFailures   int           `yaml:"breaker_failures" toml:"breaker_failures" env:"PG_BREAKER_FAILURES" env-default:"5"`
+	BreakerCooldown   time.Duration `yaml:"breaker_cooldown" toml:"breaker_cooldown" env:"PG_BREAKER_COOLDOWN" env-default:"30s"`
 }
 
+// JWT tokens are signed with Key and verified with Key or any of
+// PreviousKeys, so keys can be rotated without logging everyone out.
 type JWT struct {
-	Key string `env:"JWT_KEY"`
+	Key          string   `yaml:"key" toml:"key" env:"JWT_KEY" secret:"true" reload:"true"`
+	PreviousKeys []string `yaml:"previous_keys" toml:"previous_keys" env:"JWT_PREVIOUS_KEYS" secret:"true" reload:"true"`
 }
 
 type Steam struct {
-	APIKey string `env:"STEAM_API_KEY"`
+	APIKey string `yaml:"api_key" toml:"api_key" env:"STEAM_API_KEY" secret:"true" reload:"true"`
+	APIURL string `yaml:"api_url" toml:"api_url" env:"STEAM_API_URL" env-default:"https://api.steampowered.com"`
 }
 
 type Swagger struct {
-	URL string `env:"SWAGGER_URL"`
+	URL string `yaml:"url" toml:"url" env:"SWAGGER_URL" env-default:"/api/swagger/doc.json"`
+}
+
+type Servers struct {
+	PollInterval time.Duration `yaml:"poll_interval" toml:"poll_interval" env:"SERVERS_POLL_INTERVAL" env-default:"15s"`
+	QueryTimeout time.Duration `yaml:"query_timeout" toml:"query_timeout" env:"SERVERS_QUERY_TIMEOUT" env-default:"2s"`
+}
+
+type RCON struct {
+	Timeout time.Duration `yaml:"timeout" toml:"timeout" env:"RCON_TIMEOUT" env-default:"5s"`
+}
+
+type Events struct {
+	Heartbeat time.Duration `yaml:"heartbeat" toml:"heartbeat" env:"EVENTS_HEARTBEAT" env-default:"25s"`
+	Buffer    int           `yaml:"buffer" toml:"buffer" env:"EVENTS_BUFFER" env-default:"64"`
+}
+
+// RateLimit rates are written as limit/period, for example 10/1m.
+type RateLimit struct {
+	Backend string `yaml:"backend" toml:"backend" env:"RATE_LIMIT_BACKEND" env-default:"memory"`
+	Login   string `yaml:"login" toml:"login" env:"RATE_LIMIT_LOGIN" env-default:"10/1m" reload:"true"`
+	Refresh string `yaml:"refresh" toml:"refresh" env:"RATE_LIMIT_REFRESH" env-default:"10/1m" reload:"true"`
+	Profile string `yaml:"profile" toml:"profile" env:"RATE_LIMIT_PROFILE" env-default:"60/1m" reload:"true"`
+	Admin   string `yaml:"admin" toml:"admin" env:"RATE_LIMIT_ADMIN" env-default:"120/1m" reload:"true"`
+}
+
+// CORS origins are either exact, like https://example.com, or match any
+// subdomain, like https://*.example.com. A single * allows every origin but
+// cannot be combined with credentials.
+type CORS struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins" toml:"allowed_origins" env:"CORS_ALLOWED_ORIGINS" env-default:"*" reload:"true"`
+	AllowedMethods   []string      `yaml:"allowed_methods" toml:"allowed_methods" env:"CORS_ALLOWED_METHODS" env-default:"GET,POST,PUT,DELETE" reload:"true"`
+	AllowedHeaders   []string      `yaml:"allowed_headers" toml:"allowed_headers" env:"CORS_ALLOWED_HEADERS" env-default:"Content-Type,Authorization,X-API-Key" reload:"true"`
+	ExposedHeaders   []string      `yaml:"exposed_headers" toml:"exposed_headers" env:"CORS_EXPOSED_HEADERS" env-default:"RateLimit-Limit,RateLimit-Remaining,RateLimit-Reset,RateLimit-Policy,Retry-After,X-Request-ID,Deprecation,Sunset,Link" reload:"true"`
+	AllowCredentials bool          `yaml:"allow_credentials" toml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS" env-default:"false" reload:"true"`
+	MaxAge           time.Duration `yaml:"max_age" toml:"max_age" env:"CORS_MAX_AGE" env-default:"10m" reload:"true"`
+}
+
+// Metrics are served on a listener of their own, kept off the public one.
+type Metrics struct {
+	Addr string `yaml:"addr" toml:"addr" env:"METRICS_ADDR" env-default:"localhost:9090"`
+}
+
+// Tracing is disabled unless an OTLP endpoint is set. The standard
+// OTEL_EXPORTER_OTLP_* variables, such as headers, are honored as well.
+type Tracing struct {
+	Endpoint    string  `yaml:"endpoint" toml:"endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	ServiceName string  `yaml:"service_name" toml:"service_name" env:"OTEL_SERVICE_NAME" env-default:"backend"`
+	SampleRatio float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+}
+
+type Health struct {
+	SteamCheck    bool          `yaml:"steam_check" toml:"steam_check" env:"HEALTH_STEAM_CHECK" env-default:"false"`
+	SteamInterval time.Duration `yaml:"steam_interval" toml:"steam_interval" env:"HEALTH_STEAM_INTERVAL" env-default:"1m"`
 }
 
+// API dates are announced on the unversioned /api aliases of the v1 routes.
+type API struct {
+	LegacyDeprecated time.Time `yaml:"legacy_deprecated" toml:"legacy_deprecated" env:"API_LEGACY_DEPRECATED" env-layout:"2006-01-02" env-default:"2026-11-01"`
+	LegacySunset     time.Time `yaml:"legacy_sunset" toml:"legacy_sunset" env:"API_LEGACY_SUNSET" env-layout:"2006-01-02" env-default:"2027-05-01"`
+}
+
+// Init loads the config from the file named by CONFIG_FILE, if any, with
+// environment variables taking precedence.
 func Init() (*Config, error) {
+	return Load(os.Getenv("CONFIG_FILE"))
+}
+
+// Load reads the config with Read and validates it.
+func Load(path string) (*Config, error) {
+	cfg, err := Read(path)
+	if err != nil {
+		return nil, fmt.Errorf("Load (1): %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("Load (2): %w", err)
+	}
+
+	return cfg, nil
+}
+
+// Read reads the YAML, TOML or JSON file at path, then the environment over
+// it, including the *_FILE variants, then defaults for what is still unset.
+// path may be empty.
+func Read(path string) (*Config, error) {
 	var cfg Config
 
-	if err := cleanenv.ReadEnv(&cfg); err != nil {
-		return nil, fmt.Errorf("Init: %w", err)
+	if path != "" {
+		if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+			return nil, fmt.Errorf("Read (1): %w", err)
+		}
+	} else if err := cleanenv.ReadEnv(&cfg); err != nil {
+		return nil, fmt.Errorf("Read (2): %w", err)
+	}
+
+	if err := readSecretFiles(&cfg); err != nil {
+		return nil, fmt.Errorf("Read (3): %w", err)
 	}
 
 	return &cfg, nil
@@ -0,0 +1,226 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testKey = "0123456789abcdef0123456789abcdef"
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	path := writeFile(t, "config.yaml", `
+http:
+  port: "5000"
+  read_timeout: 30s
+postgres:
+  dsn: postgres://file
+steam:
+  api_key: steam
+cors:
+  allowed_origins: [https://example.com]
+`)
+
+	t.Setenv("HTTP_PORT", "6000")
+	t.Setenv("JWT_KEY_FILE", writeFile(t, "jwt_key", testKey+"\n"))
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.HTTP.Port != "6000" {
+		t.Errorf("env does not override the file: port %q", cfg.HTTP.Port)
+	}
+
+	if cfg.HTTP.ReadTimeout != 30*time.Second || cfg.Postgres.DSN != "postgres://file" {
+		t.Errorf("file values not read: %+v %+v", cfg.HTTP, cfg.Postgres)
+	}
+
+	if cfg.HTTP.WriteTimeout != 15*time.Second || cfg.RateLimit.Backend != "memory" {
+		t.Errorf("defaults not applied: %+v %+v", cfg.HTTP, cfg.RateLimit)
+	}
+
+	if cfg.JWT.Key != testKey {
+		t.Errorf("JWT_KEY_FILE not read: %q", cfg.JWT.Key)
+	}
+
+	if len(cfg.CORS.AllowedOrigins) != 1 || cfg.CORS.AllowedOrigins[0] != "https://example.com" {
+		t.Errorf("origins %v", cfg.CORS.AllowedOrigins)
+	}
+}
+
+func TestSecretFileConflict(t *testing.T) {
+	t.Setenv("JWT_KEY", testKey)
+	t.Setenv("JWT_KEY_FILE", writeFile(t, "jwt_key", testKey))
+
+	if _, err := Read(""); err == nil || !strings.Contains(err.Error(), "JWT_KEY and JWT_KEY_FILE are both set") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestSecretFileTypes(t *testing.T) {
+	t.Setenv("JWT_KEY", testKey)
+	t.Setenv("JWT_PREVIOUS_KEYS_FILE", writeFile(t, "previous", "old-one,old-two\n"))
+	t.Setenv("PG_QUERY_TIMEOUT_FILE", writeFile(t, "timeout", "3s"))
+
+	cfg, err := Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.JWT.PreviousKeys) != 2 || cfg.JWT.PreviousKeys[1] != "old-two" {
+		t.Errorf("JWT_PREVIOUS_KEYS_FILE not read: %q", cfg.JWT.PreviousKeys)
+	}
+
+	if cfg.Postgres.QueryTimeout != 3*time.Second {
+		t.Errorf("PG_QUERY_TIMEOUT_FILE not read: %s", cfg.Postgres.QueryTimeout)
+	}
+
+	if cfg.HTTP.Host != "" {
+		t.Errorf("HTTP_HOST defaults to %q, want all interfaces", cfg.HTTP.Host)
+	}
+
+	t.Setenv("PG_QUERY_TIMEOUT_FILE", writeFile(t, "timeout", "soon"))
+
+	if _, err := Read(""); err == nil || !strings.Contains(err.Error(), "PG_QUERY_TIMEOUT_FILE") {
+		t.Errorf("unparsable file: got %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Setenv("HTTP_PORT", "http")
+	t.Setenv("JWT_KEY", "short")
+	t.Setenv("RATE_LIMIT_LOGIN", "ten")
+	t.Setenv("METRICS_ADDR", "9090")
+
+	_, err := Load("")
+	if err == nil {
+		t.Fatal("invalid config loaded")
+	}
+
+	for _, want := range []string{
+		`HTTP_PORT: "http" is not a port`,
+		"PG_DSN: is required",
+		"JWT_KEY: must be at least 32 bytes, got 5",
+		"STEAM_API_KEY: is required",
+		`RATE_LIMIT_LOGIN: "ten" is not in limit/period form`,
+		`METRICS_ADDR: "9090" is not a host:port address`,
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("%q missing from %v", want, err)
+		}
+	}
+}
+
+func TestPrintRedacted(t *testing.T) {
+	t.Setenv("JWT_KEY", testKey)
+	t.Setenv("PG_DSN", "")
+
+	cfg, err := Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Print(&buf, cfg, true); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+
+	for _, want := range []string{"JWT_KEY=REDACTED\n", "PG_DSN=\n", "HTTP_READ_TIMEOUT=15s\n", "API_LEGACY_SUNSET=2027-05-01\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%q missing from\n%s", want, out)
+		}
+	}
+
+	if strings.Contains(out, testKey) {
+		t.Error("secret printed")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Setenv("JWT_KEY", testKey)
+
+	prev, err := Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("JWT_KEY", testKey+"2")
+	t.Setenv("RATE_LIMIT_LOGIN", "5/1m")
+	t.Setenv("HTTP_PORT", "5000")
+
+	next, err := Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Change{
+		{Env: "HTTP_PORT", From: "4000", To: "5000"},
+		{Env: "JWT_KEY", From: redacted, To: redacted, Reload: true},
+		{Env: "RATE_LIMIT_LOGIN", From: "10/1m", To: "5/1m", Reload: true},
+	}
+
+	got := Diff(prev, next)
+	if len(got) != len(want) {
+		t.Fatalf("got %+v", got)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d: %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReloaded(t *testing.T) {
+	t.Setenv("JWT_KEY", testKey)
+
+	running, err := Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("RATE_LIMIT_LOGIN", "5/1m")
+	t.Setenv("HTTP_PORT", "5000")
+
+	next, err := Read("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Reloaded(running, next)
+
+	if cfg.RateLimit.Login != "5/1m" {
+		t.Errorf("reloadable setting not taken: %q", cfg.RateLimit.Login)
+	}
+
+	if cfg.HTTP.Port != "4000" {
+		t.Errorf("restart-only setting taken: %q", cfg.HTTP.Port)
+	}
+
+	if running.RateLimit.Login != "10/1m" {
+		t.Errorf("running config modified: %q", running.RateLimit.Login)
+	}
+
+	// The port change is still pending on the next reload.
+	if got := Diff(cfg, next); len(got) != 1 || got[0].Env != "HTTP_PORT" {
+		t.Errorf("got %+v", got)
+	}
+}
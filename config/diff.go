@@ -0,0 +1,58 @@
+package config
+
+// Change is a setting that differs between two configs. Secrets are shown
+// as REDACTED.
+type Change struct {
+	Env  string
+	From string
+	To   string
+	// Reload is set when the change is applied without a restart.
+	Reload bool
+}
+
+// Diff lists the settings that differ from prev to next.
+func Diff(prev, next *Config) []Change {
+	var (
+		before  = fields(prev)
+		after   = fields(next)
+		changes []Change
+	)
+
+	for i, f := range after {
+		from, to := before[i].String(), f.String()
+		if from == to {
+			continue
+		}
+
+		if f.secret {
+			from, to = redactValue(from), redactValue(to)
+		}
+
+		changes = append(changes, Change{Env: f.env, From: from, To: to, Reload: f.reload})
+	}
+
+	return changes
+}
+
+// Reloaded is running with the settings tagged reload taken from next: the
+// config a process started with running has after reloading next.
+func Reloaded(running, next *Config) *Config {
+	cfg := *running
+
+	after := fields(next)
+	for i, f := range fields(&cfg) {
+		if f.reload {
+			f.value.Set(after[i].value)
+		}
+	}
+
+	return &cfg
+}
+
+func redactValue(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return redacted
+}
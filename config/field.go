@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// field is a setting of the config as named by its env tag.
+type field struct {
+	env    string
+	layout string
+	secret bool
+	reload bool
+	value  reflect.Value
+}
+
+// fields lists the settings of cfg in declaration order.
+func fields(cfg *Config) []field {
+	var out []field
+
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			sf, fv := t.Field(i), v.Field(i)
+
+			env, ok := sf.Tag.Lookup("env")
+			if !ok {
+				if fv.Kind() == reflect.Struct {
+					walk(fv)
+				}
+
+				continue
+			}
+
+			out = append(out, field{
+				env:    env,
+				layout: sf.Tag.Get("env-layout"),
+				secret: sf.Tag.Get("secret") == "true",
+				reload: sf.Tag.Get("reload") == "true",
+				value:  fv,
+			})
+		}
+	}
+
+	walk(reflect.ValueOf(cfg).Elem())
+
+	return out
+}
+
+// String formats the value the way it is written in the environment.
+func (f field) String() string {
+	switch v := f.value.Interface().(type) {
+	case time.Duration:
+		return v.String()
+	case time.Time:
+		return v.Format(f.layout)
+	case []string:
+		return strings.Join(v, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// set parses s the way it is written in the environment into the setting.
+func (f field) set(s string) error {
+	switch f.value.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+
+		f.value.SetInt(int64(d))
+	case time.Time:
+		t, err := time.Parse(f.layout, s)
+		if err != nil {
+			return err
+		}
+
+		f.value.Set(reflect.ValueOf(t))
+	case []string:
+		f.value.Set(reflect.ValueOf(strings.Split(s, ",")))
+	case string:
+		f.value.SetString(s)
+	case int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+
+		f.value.SetInt(int64(n))
+	case bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+
+		f.value.SetBool(b)
+	case float64:
+		x, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+
+		f.value.SetFloat(x)
+	default:
+		return fmt.Errorf("unsupported type %s", f.value.Type())
+	}
+
+	return nil
+}
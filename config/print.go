@@ -0,0 +1,25 @@
+package config
+
+import (
+	"fmt"
+	"io"
+)
+
+const redacted = "REDACTED"
+
+// Print writes cfg to w as VAR=value lines, which can be used as an .env
+// file. With redact, the values of secrets such as JWT_KEY are hidden.
+func Print(w io.Writer, cfg *Config, redact bool) error {
+	for _, f := range fields(cfg) {
+		value := f.String()
+		if redact && f.secret {
+			value = redactValue(value)
+		}
+
+		if _, err := fmt.Fprintf(w, "%s=%s\n", f.env, value); err != nil {
+			return fmt.Errorf("Print: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readSecretFiles sets settings from the file named by their *_FILE
+// variable, such as JWT_KEY_FILE=/run/secrets/jwt_key, as Docker secrets are
+// mounted. Setting both the variable and its *_FILE variant is an error.
+func readSecretFiles(cfg *Config) error {
+	var errs []error
+
+	for _, f := range fields(cfg) {
+		path := os.Getenv(f.env + "_FILE")
+		if path == "" {
+			continue
+		}
+
+		if _, ok := os.LookupEnv(f.env); ok {
+			errs = append(errs, fmt.Errorf("%s and %s_FILE are both set", f.env, f.env))
+
+			continue
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s_FILE: %w", f.env, err))
+
+			continue
+		}
+
+		if err := f.set(strings.TrimRight(string(b), "\r\n")); err != nil {
+			errs = append(errs, fmt.Errorf("%s_FILE: %w", f.env, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MinJWTKeyLength is the shortest HMAC key accepted, the size of the SHA-256
+// output tokens are signed with.
+const MinJWTKeyLength = 32
+
+// Validate reports every invalid setting at once, each by its variable name.
+func (c *Config) Validate() error {
+	var errs []error
+
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	_, err := logrus.ParseLevel(c.Log.Level)
+	check(err == nil, "LOG_LEVEL: %q is not a level", c.Log.Level)
+
+	port, err := strconv.Atoi(c.HTTP.Port)
+	check(err == nil && port > 0 && port < 1<<16, "HTTP_PORT: %q is not a port", c.HTTP.Port)
+	check(c.HTTP.ReadTimeout >= 0, "HTTP_READ_TIMEOUT: must not be negative")
+	check(c.HTTP.WriteTimeout >= 0, "HTTP_WRITE_TIMEOUT: must not be negative")
+	check(c.HTTP.DrainDelay >= 0, "HTTP_DRAIN_DELAY: must not be negative")
+	check(c.HTTP.ShutdownTimeout > c.HTTP.DrainDelay, "HTTP_SHUTDOWN_TIMEOUT: must be longer than HTTP_DRAIN_DELAY")
+	check(c.HTTP.ReadHeaderTimeout >= 0, "HTTP_READ_HEADER_TIMEOUT: must not be negative")
+	check(c.HTTP.IdleTimeout >= 0, "HTTP_IDLE_TIMEOUT: must not be negative")
+	check(c.HTTP.MaxHeaderBytes > 0, "HTTP_MAX_HEADER_BYTES: must be positive")
+	for _, cidr := range c.HTTP.TrustedProxies {
+		_, prefixErr := netip.ParsePrefix(cidr)
+		_, addrErr := netip.ParseAddr(cidr)
+		check(prefixErr == nil || addrErr == nil, "HTTP_TRUSTED_PROXIES: %q is neither a CIDR nor an address", cidr)
+	}
+
+	check(!c.HTTP.H2C || !c.TLS.Enabled(), "HTTP_H2C: cannot be combined with TLS, which serves HTTP/2 already")
+
+	check((c.TLS.CertFile == "") == (c.TLS.KeyFile == ""), "TLS_CERT_FILE, TLS_KEY_FILE: must be set together")
+	check(c.TLS.ClientCAFile == "" || c.TLS.Enabled(), "TLS_CLIENT_CA_FILE: requires TLS_CERT_FILE")
+	check(c.TLS.ReloadInterval > 0, "TLS_RELOAD_INTERVAL: must be positive")
+
+	check(c.Postgres.DSN != "", "PG_DSN: is required")
+	check(c.Postgres.MaxConns > 0, "PG_MAX_CONNS: must be positive")
+	check(c.Postgres.MinConns >= 0 && c.Postgres.MinConns <= c.Postgres.MaxConns, "PG_MIN_CONNS: must be between 0 and PG_MAX_CONNS")
+	check(c.Postgres.MaxConnLifetime > 0, "PG_MAX_CONN_LIFETIME: must be positive")
+	check(c.Postgres.MaxConnIdleTime > 0, "PG_MAX_CONN_IDLE_TIME: must be positive")
+	check(c.Postgres.HealthCheckPeriod > 0, "PG_HEALTH_CHECK_PERIOD: must be positive")
+	check(c.Postgres.StatementTimeout >= 0, "PG_STATEMENT_TIMEOUT: must not be negative")
+	check(c.Postgres.QueryTimeout > 0, "PG_QUERY_TIMEOUT: must be positive")
+	check(c.Postgres.ConnectAttempts > 0, "PG_CONNECT_ATTEMPTS: must be positive")
+	check(c.Postgres.ConnectBackoff > 0, "PG_CONNECT_BACKOFF: must be positive")
+	check(c.Postgres.BreakerFailures > 0, "PG_BREAKER_FAILURES: must be positive")
+	check(c.Postgres.BreakerCooldown > 0, "PG_BREAKER_COOLDOWN: must be positive")
+	check(len(c.JWT.Key) >= MinJWTKeyLength, "JWT_KEY: must be at least %d bytes, got %d", MinJWTKeyLength, len(c.JWT.Key))
+	check(c.Steam.APIKey != "", "STEAM_API_KEY: is required")
+	steamURL, err := url.Parse(c.Steam.APIURL)
+	check(err == nil && (steamURL.Scheme == "http" || steamURL.Scheme == "https") && steamURL.Host != "",
+		"STEAM_API_URL: %q is not an http(s) URL", c.Steam.APIURL)
+
+	check(c.Servers.PollInterval > 0, "SERVERS_POLL_INTERVAL: must be positive")
+	check(c.Servers.QueryTimeout > 0, "SERVERS_QUERY_TIMEOUT: must be positive")
+	check(c.RCON.Timeout > 0, "RCON_TIMEOUT: must be positive")
+	check(c.Events.Heartbeat > 0, "EVENTS_HEARTBEAT: must be positive")
+	check(c.Events.Buffer > 0, "EVENTS_BUFFER: must be positive")
+
+	check(c.RateLimit.Backend == "memory" || c.RateLimit.Backend == "postgres",
+		"RATE_LIMIT_BACKEND: %q is neither memory nor postgres", c.RateLimit.Backend)
+
+	for _, rate := range []struct{ env, value string }{
+		{"RATE_LIMIT_LOGIN", c.RateLimit.Login},
+		{"RATE_LIMIT_REFRESH", c.RateLimit.Refresh},
+		{"RATE_LIMIT_PROFILE", c.RateLimit.Profile},
+		{"RATE_LIMIT_ADMIN", c.RateLimit.Admin},
+	} {
+		check(isRate(rate.value), "%s: %q is not in limit/period form", rate.env, rate.value)
+	}
+
+	check(!c.CORS.AllowCredentials || !slices.Contains(c.CORS.AllowedOrigins, "*"),
+		"CORS_ALLOW_CREDENTIALS: cannot be combined with CORS_ALLOWED_ORIGINS=*")
+	check(c.CORS.MaxAge >= 0, "CORS_MAX_AGE: must not be negative")
+
+	_, metricsPort, err := net.SplitHostPort(c.Metrics.Addr)
+	check(err == nil && metricsPort != "", "METRICS_ADDR: %q is not a host:port address", c.Metrics.Addr)
+
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO: must be between 0 and 1")
+	check(c.Health.SteamInterval > 0, "HEALTH_STEAM_INTERVAL: must be positive")
+
+	check(!c.API.LegacySunset.Before(c.API.LegacyDeprecated), "API_LEGACY_SUNSET: must not be before API_LEGACY_DEPRECATED")
+
+	return errors.Join(errs...)
+}
+
+// isRate reports whether s is a positive limit per positive period, such as
+// 10/1m.
+func isRate(s string) bool {
+	limit, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return false
+	}
+
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		return false
+	}
+
+	d, err := time.ParseDuration(period)
+
+	return err == nil && d > 0
+}
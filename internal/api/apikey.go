@@ -0,0 +1,185 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/sirupsen/logrus"
+)
+
+type apiKeyService interface {
+	Issue(context.Context, int64, m.APIKeyInput) (m.IssuedAPIKey, error)
+	Rotate(context.Context, int64, int64) (m.IssuedAPIKey, error)
+	Revoke(context.Context, int64, int64) error
+	List(context.Context, int64) ([]m.APIKey, error)
+}
+
+type APIKeyAPI struct {
+	logger  *logrus.Logger
+	service apiKeyService
+}
+
+func NewAPIKeyAPI(logger *logrus.Logger, service apiKeyService) *APIKeyAPI {
+	return &APIKeyAPI{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// @Summary Lists API keys of game server
+// @Tags keys
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Server ID"
+// @Success 200 {array} m.APIKey
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/servers/{id}/keys [get]
+func (a *APIKeyAPI) List(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidID)
+
+		return
+	}
+
+	keys, err := a.service.List(r.Context(), id)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusOK, keys)
+}
+
+// @Summary Issues API key for game server
+// @Description The plain key is returned only once.
+// @Tags keys
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Server ID"
+// @Param key body m.APIKeyInput true "Key"
+// @Success 201 {object} m.IssuedAPIKey
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 404 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/servers/{id}/keys [post]
+func (a *APIKeyAPI) Issue(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidID)
+
+		return
+	}
+
+	var input m.APIKeyInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidBody)
+
+		return
+	}
+
+	key, err := a.service.Issue(r.Context(), id, input)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusCreated, key)
+}
+
+// @Summary Rotates API key of game server
+// @Description Revokes the key and issues a new one with the same scopes. The plain key is returned only once.
+// @Tags keys
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Server ID"
+// @Param keyID path int true "Key ID"
+// @Success 201 {object} m.IssuedAPIKey
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 404 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/servers/{id}/keys/{keyID}/rotate [post]
+func (a *APIKeyAPI) Rotate(w http.ResponseWriter, r *http.Request) {
+	serverID, keyID, err := keyPathIDs(r)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidID)
+
+		return
+	}
+
+	key, err := a.service.Rotate(r.Context(), serverID, keyID)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusCreated, key)
+}
+
+// @Summary Revokes API key of game server
+// @Tags keys
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Server ID"
+// @Param keyID path int true "Key ID"
+// @Success 204 {object} nil
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 404 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/servers/{id}/keys/{keyID} [delete]
+func (a *APIKeyAPI) Revoke(w http.ResponseWriter, r *http.Request) {
+	serverID, keyID, err := keyPathIDs(r)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidID)
+
+		return
+	}
+
+	if err := a.service.Revoke(r.Context(), serverID, keyID); err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
+
+func keyPathIDs(r *http.Request) (int64, int64, error) {
+	serverID, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	keyID, err := strconv.ParseInt(r.PathValue("keyID"), 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return serverID, keyID, nil
+}
@@ -2,21 +2,23 @@ package api
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
 	"net/url"
+	"sync/atomic"
 
 	steamauth "github.com/TeddiO/GoSteamAuth/src"
 	"github.com/cs2-server/backend/config"
+	"github.com/cs2-server/backend/internal/middleware"
 	m "github.com/cs2-server/backend/internal/model"
 	"github.com/cs2-server/backend/internal/render"
+	"github.com/cs2-server/backend/pkg/jwt"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	ErrMethodNotAllowed = "method not allowed"
-	ErrInvalidAuth      = "invalid auth"
-	ErrParamNotSet      = "param is not set"
+	ErrInvalidAuth        = "invalid auth"
+	ErrProfileUnavailable = "profile is unavailable"
 )
 
 type jwtGenerator interface {
@@ -27,20 +29,35 @@ type authService interface {
 	GetProfile(context.Context, string, string) (m.Profile, error)
 }
 
+//go:generate mockgen -source=auth.go -destination=auth_mock_test.go -package=api
+
 type AuthAPI struct {
-	cfg     *config.Config
-	logger  *logrus.Logger
-	jwt     jwtGenerator
-	service authService
+	cfg      *config.Config
+	steamKey atomic.Pointer[string]
+	logger   *logrus.Logger
+	jwt      jwtGenerator
+	service  authService
+	// validate checks the OpenID response with Steam; tests replace it.
+	validate func(map[string]string) (string, bool, error)
 }
 
 func NewAuthAPI(cfg *config.Config, logger *logrus.Logger, jwt jwtGenerator, service authService) *AuthAPI {
-	return &AuthAPI{
-		cfg:     cfg,
-		logger:  logger,
-		jwt:     jwt,
-		service: service,
+	a := &AuthAPI{
+		cfg:      cfg,
+		logger:   logger,
+		jwt:      jwt,
+		service:  service,
+		validate: steamauth.ValidateResponse,
 	}
+
+	a.SetSteamAPIKey(cfg.Steam.APIKey)
+
+	return a
+}
+
+// SetSteamAPIKey replaces the key profiles are fetched with.
+func (a *AuthAPI) SetSteamAPIKey(key string) {
+	a.steamKey.Store(&key)
 }
 
 // @Summary Redirects client to Steam authentication page
@@ -48,17 +65,9 @@ func NewAuthAPI(cfg *config.Config, logger *logrus.Logger, jwt jwtGenerator, ser
 // @Accept json
 // @Produce json
 // @Success 302 {object} nil
-// @Failure 405 {object} render.Err
-// @Router /api/auth/login [get]
+// @Router /api/v1/auth/login [get]
 func (a *AuthAPI) Login(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		a.logger.Errorln(ErrMethodNotAllowed)
-		render.Error(w, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
-
-		return
-	}
-
-	query := fmt.Sprintf("http://%s:%s/api/auth/process", a.cfg.HTTP.Host, a.cfg.HTTP.Port)
+	query := middleware.BaseURL(r) + V1.Prefix() + "/auth/process"
 	steamauth.RedirectClient(w, r, steamauth.BuildQueryString(query))
 }
 
@@ -68,36 +77,29 @@ func (a *AuthAPI) Login(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Success 200 {object} m.JWT
 // @Failure 400 {object} render.Err
-// @Failure 405 {object} render.Err
 // @Failure 500 {object} render.Err
-// @Router /api/auth/process [get]
+// @Router /api/v1/auth/process [get]
 func (a *AuthAPI) ProcessLogin(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		a.logger.Errorln(ErrMethodNotAllowed)
-		render.Error(w, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
-
-		return
-	}
-
 	query, err := url.ParseQuery(r.URL.RawQuery)
 	if err != nil {
-		a.logger.Errorln(err)
+		a.logger.WithContext(r.Context()).Errorln(err)
 		render.Error(w, http.StatusInternalServerError, err.Error())
 
+		return
 	}
 
 	queryMap := steamauth.ValuesToMap(query)
 
-	steamID, isValid, err := steamauth.ValidateResponse(queryMap)
+	steamID, isValid, err := a.validate(queryMap)
 	if err != nil {
-		a.logger.Errorln(err)
+		a.logger.WithContext(r.Context()).Errorln(err)
 		render.Error(w, http.StatusInternalServerError, err.Error())
 
 		return
 	}
 
 	if !isValid {
-		a.logger.Errorln(ErrInvalidAuth)
+		a.logger.WithContext(r.Context()).Errorln(ErrInvalidAuth)
 		render.Error(w, http.StatusInternalServerError, ErrInvalidAuth)
 
 		return
@@ -105,48 +107,53 @@ func (a *AuthAPI) ProcessLogin(w http.ResponseWriter, r *http.Request) {
 
 	tokens, err := a.jwt.GenerateTokens(steamID)
 	if err != nil {
-		a.logger.Errorln(err)
+		a.logger.WithContext(r.Context()).Errorln(err)
 		render.Error(w, http.StatusInternalServerError, ErrInvalidAuth)
+
+		return
 	}
 
-	render.JSON(w, http.StatusOK, tokens)
+	respond(w, r, http.StatusOK, tokens)
 }
 
 // @Summary Refreshes JWT tokens
+// @Description Takes the refresh token as bearer token and issues new tokens for its player.
 // @Tags auth
 // @Security BearerAuth
 // @Produce json
-// @Param id formData string true "User ID"
+// @Param id formData string false "User ID, must be the token's player"
 // @Success 200 {object} m.JWT
-// @Failure 400 {object} render.Err
 // @Failure 401 {object} render.Err
-// @Failure 405 {object} render.Err
+// @Failure 403 {object} render.Err
 // @Failure 500 {object} render.Err
-// @Router /api/auth/refresh [post]
+// @Router /api/v1/auth/refresh [post]
 func (a *AuthAPI) RefreshToken(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		a.logger.Errorln(ErrMethodNotAllowed)
-		render.Error(w, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
+	steamID, ok := jwt.SteamID(r.Context())
+	if !ok {
+		a.logger.WithContext(r.Context()).Errorln(ErrUnauthorized)
+		render.Error(w, http.StatusUnauthorized, ErrUnauthorized)
 
 		return
 	}
 
-	id := r.FormValue("id")
-
-	if id == "" {
-		a.logger.Errorln(ErrParamNotSet)
-		render.Error(w, http.StatusBadRequest, ErrParamNotSet)
+	// Tokens are only ever issued for the player the refresh token belongs
+	// to; id is accepted for older clients that still send it.
+	if id := r.FormValue("id"); id != "" && id != steamID {
+		a.logger.WithContext(r.Context()).Errorf("RefreshToken: %s asked for tokens of %s", steamID, id)
+		render.Error(w, http.StatusForbidden, middleware.ErrForbidden)
 
 		return
 	}
 
-	tokens, err := a.jwt.GenerateTokens(id)
+	tokens, err := a.jwt.GenerateTokens(steamID)
 	if err != nil {
-		a.logger.Errorln(err)
+		a.logger.WithContext(r.Context()).Errorln(err)
 		render.Error(w, http.StatusInternalServerError, err.Error())
+
+		return
 	}
 
-	render.JSON(w, http.StatusOK, tokens)
+	respond(w, r, http.StatusOK, tokens)
 }
 
 // @Summary Retrieves user profile
@@ -158,25 +165,25 @@ func (a *AuthAPI) RefreshToken(w http.ResponseWriter, r *http.Request) {
 // @Success 200 {object} m.Profile
 // @Failure 400 {object} render.Err
 // @Failure 401 {object} render.Err
+// @Failure 404 {object} render.Err
 // @Failure 500 {object} render.Err
-// @Router /api/profile/{id} [get]
+// @Router /api/v1/profile/{id} [get]
 func (a *AuthAPI) GetProfile(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		a.logger.Errorln(ErrMethodNotAllowed)
-		render.Error(w, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
-
-		return
-	}
-
 	id := r.PathValue("id")
 
-	profile, err := a.service.GetProfile(r.Context(), a.cfg.Steam.APIKey, id)
+	profile, err := a.service.GetProfile(r.Context(), *a.steamKey.Load(), id)
 	if err != nil {
-		a.logger.Errorln(err)
-		render.Error(w, http.StatusInternalServerError, err.Error())
+		a.logger.WithContext(r.Context()).Errorln(err)
+
+		// Steam and database errors are only logged.
+		if errors.Is(err, m.ErrNotFound) {
+			renderServiceError(w, err)
+		} else {
+			render.Error(w, http.StatusInternalServerError, ErrProfileUnavailable)
+		}
 
 		return
 	}
 
-	render.JSON(w, http.StatusOK, profile)
+	respond(w, r, http.StatusOK, profile)
 }
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: auth.go
+//
+// Generated by this command:
+//
+//	mockgen -source=auth.go -destination=auth_mock_test.go -package=api
+//
+
+// Package api is a generated GoMock package.
+package api
+
+import (
+	context "context"
+	reflect "reflect"
+
+	model "github.com/cs2-server/backend/internal/model"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockjwtGenerator is a mock of jwtGenerator interface.
+type MockjwtGenerator struct {
+	ctrl     *gomock.Controller
+	recorder *MockjwtGeneratorMockRecorder
+}
+
+// MockjwtGeneratorMockRecorder is the mock recorder for MockjwtGenerator.
+type MockjwtGeneratorMockRecorder struct {
+	mock *MockjwtGenerator
+}
+
+// NewMockjwtGenerator creates a new mock instance.
+func NewMockjwtGenerator(ctrl *gomock.Controller) *MockjwtGenerator {
+	mock := &MockjwtGenerator{ctrl: ctrl}
+	mock.recorder = &MockjwtGeneratorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockjwtGenerator) EXPECT() *MockjwtGeneratorMockRecorder {
+	return m.recorder
+}
+
+// GenerateTokens mocks base method.
+func (m *MockjwtGenerator) GenerateTokens(arg0 string) (model.JWT, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GenerateTokens", arg0)
+	ret0, _ := ret[0].(model.JWT)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GenerateTokens indicates an expected call of GenerateTokens.
+func (mr *MockjwtGeneratorMockRecorder) GenerateTokens(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GenerateTokens", reflect.TypeOf((*MockjwtGenerator)(nil).GenerateTokens), arg0)
+}
+
+// MockauthService is a mock of authService interface.
+type MockauthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockauthServiceMockRecorder
+}
+
+// MockauthServiceMockRecorder is the mock recorder for MockauthService.
+type MockauthServiceMockRecorder struct {
+	mock *MockauthService
+}
+
+// NewMockauthService creates a new mock instance.
+func NewMockauthService(ctrl *gomock.Controller) *MockauthService {
+	mock := &MockauthService{ctrl: ctrl}
+	mock.recorder = &MockauthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockauthService) EXPECT() *MockauthServiceMockRecorder {
+	return m.recorder
+}
+
+// GetProfile mocks base method.
+func (m *MockauthService) GetProfile(arg0 context.Context, arg1, arg2 string) (model.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", arg0, arg1, arg2)
+	ret0, _ := ret[0].(model.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockauthServiceMockRecorder) GetProfile(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockauthService)(nil).GetProfile), arg0, arg1, arg2)
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cs2-server/backend/config"
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/pkg/jwt"
+	"github.com/sirupsen/logrus"
+	"go.uber.org/mock/gomock"
+)
+
+const testSteamID = "76561198000000001"
+
+var testTokens = m.JWT{ID: testSteamID, AccessToken: "access", RefreshToken: "refresh"}
+
+func newTestAuthAPI(t *testing.T) (*AuthAPI, *MockjwtGenerator, *MockauthService) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	gen := NewMockjwtGenerator(ctrl)
+	service := NewMockauthService(ctrl)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	cfg := &config.Config{}
+	cfg.Steam.APIKey = "steam-key"
+
+	return NewAuthAPI(cfg, logger, gen, service), gen, service
+}
+
+// checkResponse fails unless the response has status and a single JSON body
+// containing want.
+func checkResponse(t *testing.T, w *httptest.ResponseRecorder, status int, want string) {
+	t.Helper()
+
+	if w.Code != status {
+		t.Fatalf("got status %d, want %d: %s", w.Code, status, w.Body)
+	}
+
+	dec := json.NewDecoder(w.Body)
+
+	var body json.RawMessage
+	if err := dec.Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if dec.More() {
+		t.Fatalf("response has more than one body: %s", w.Body)
+	}
+
+	if !strings.Contains(string(body), want) {
+		t.Fatalf("body %s does not contain %s", body, want)
+	}
+}
+
+func TestProcessLogin(t *testing.T) {
+	errSteam := errors.New("steam unreachable")
+
+	tests := []struct {
+		name     string
+		query    string
+		validate func(map[string]string) (string, bool, error)
+		setup    func(*MockjwtGenerator)
+		status   int
+		want     string
+	}{
+		{
+			name:   "malformed query",
+			query:  "openid.mode=%zz",
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:     "steam error",
+			validate: func(map[string]string) (string, bool, error) { return "", false, errSteam },
+			status:   http.StatusInternalServerError,
+			want:     errSteam.Error(),
+		},
+		{
+			name:     "rejected by steam",
+			validate: func(map[string]string) (string, bool, error) { return "", false, nil },
+			status:   http.StatusInternalServerError,
+			want:     ErrInvalidAuth,
+		},
+		{
+			name:     "token error",
+			validate: func(map[string]string) (string, bool, error) { return testSteamID, true, nil },
+			setup: func(gen *MockjwtGenerator) {
+				gen.EXPECT().GenerateTokens(testSteamID).Return(m.JWT{}, errors.New("sign"))
+			},
+			status: http.StatusInternalServerError,
+			want:   ErrInvalidAuth,
+		},
+		{
+			name:     "ok",
+			query:    "openid.claimed_id=https%3A%2F%2Fsteamcommunity.com%2Fopenid%2Fid%2F" + testSteamID,
+			validate: func(map[string]string) (string, bool, error) { return testSteamID, true, nil },
+			setup: func(gen *MockjwtGenerator) {
+				gen.EXPECT().GenerateTokens(testSteamID).Return(testTokens, nil)
+			},
+			status: http.StatusOK,
+			want:   `"access_token":"access"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, gen, _ := newTestAuthAPI(t)
+
+			a.validate = func(map[string]string) (string, bool, error) {
+				t.Fatal("steam must not be asked")
+				return "", false, nil
+			}
+			if tt.validate != nil {
+				a.validate = tt.validate
+			}
+
+			if tt.setup != nil {
+				tt.setup(gen)
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/auth/process", nil)
+			r.URL.RawQuery = tt.query
+
+			a.ProcessLogin(w, r)
+
+			checkResponse(t, w, tt.status, tt.want)
+		})
+	}
+}
+
+func TestRefreshToken(t *testing.T) {
+	issuer := jwt.New("refresh-test-key-of-at-least-32-bytes", nil, nil)
+
+	real, err := issuer.GenerateTokens(testSteamID)
+	if err != nil {
+		t.Fatalf("GenerateTokens: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		token  string
+		id     string
+		setup  func(*MockjwtGenerator)
+		status int
+		want   string
+	}{
+		{
+			name:   "missing token",
+			status: http.StatusUnauthorized,
+		},
+		{
+			name:   "access token",
+			token:  real.AccessToken,
+			status: http.StatusUnauthorized,
+			want:   jwt.ErrTokenType.Error(),
+		},
+		{
+			name:   "other player",
+			token:  real.RefreshToken,
+			id:     "76561198000000002",
+			status: http.StatusForbidden,
+		},
+		{
+			name:  "token error",
+			token: real.RefreshToken,
+			setup: func(gen *MockjwtGenerator) {
+				gen.EXPECT().GenerateTokens(testSteamID).Return(m.JWT{}, errors.New("sign"))
+			},
+			status: http.StatusInternalServerError,
+			want:   "sign",
+		},
+		{
+			name:  "ok",
+			token: real.RefreshToken,
+			setup: func(gen *MockjwtGenerator) {
+				gen.EXPECT().GenerateTokens(testSteamID).Return(testTokens, nil)
+			},
+			status: http.StatusOK,
+			want:   `"refresh_token":"refresh"`,
+		},
+		{
+			name:  "ok with own id",
+			token: real.RefreshToken,
+			id:    testSteamID,
+			setup: func(gen *MockjwtGenerator) {
+				gen.EXPECT().GenerateTokens(testSteamID).Return(testTokens, nil)
+			},
+			status: http.StatusOK,
+			want:   `"access_token":"access"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, gen, _ := newTestAuthAPI(t)
+			if tt.setup != nil {
+				tt.setup(gen)
+			}
+
+			form := url.Values{}
+			if tt.id != "" {
+				form.Set("id", tt.id)
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/auth/refresh", strings.NewReader(form.Encode()))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+			if tt.token != "" {
+				r.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+
+			issuer.Refresh(a.RefreshToken)(w, r)
+
+			checkResponse(t, w, tt.status, tt.want)
+		})
+	}
+}
+
+func TestGetProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile m.Profile
+		err     error
+		status  int
+		want    string
+	}{
+		{
+			name:   "unknown player",
+			err:    m.ErrNotFound,
+			status: http.StatusNotFound,
+			want:   ErrNotFound,
+		},
+		{
+			name:   "service error",
+			err:    errors.New("steam api responded with 503"),
+			status: http.StatusInternalServerError,
+			want:   ErrProfileUnavailable,
+		},
+		{
+			name:    "degraded",
+			profile: m.Profile{ID: testSteamID, StatsUnavailable: true},
+			status:  http.StatusOK,
+			want:    `"stats_unavailable":true`,
+		},
+		{
+			name:    "ok",
+			profile: m.Profile{ID: testSteamID, Kills: 120},
+			status:  http.StatusOK,
+			want:    `"kills":120`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _, service := newTestAuthAPI(t)
+
+			service.EXPECT().
+				GetProfile(gomock.Any(), "steam-key", testSteamID).
+				Return(tt.profile, tt.err)
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/api/v1/profile/"+testSteamID, nil)
+			r.SetPathValue("id", testSteamID)
+
+			a.GetProfile(w, r)
+
+			checkResponse(t, w, tt.status, tt.want)
+		})
+	}
+}
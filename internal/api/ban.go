@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/sirupsen/logrus"
+)
+
+type banService interface {
+	CreateBan(context.Context, string, m.BanInput) (m.Ban, error)
+	ListBans(context.Context, m.BanFilter) ([]m.Ban, error)
+	LiftBan(context.Context, int64, string, string) (m.Ban, error)
+	CheckPlayer(context.Context, string, string) (m.BanCheck, error)
+}
+
+type BanAPI struct {
+	logger  *logrus.Logger
+	service banService
+}
+
+func NewBanAPI(logger *logrus.Logger, service banService) *BanAPI {
+	return &BanAPI{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// @Summary Lists bans, mutes and gags
+// @Tags bans
+// @Produce json
+// @Param steam_id query string false "Player SteamID"
+// @Param type query string false "Punishment type" Enums(ban, mute, gag)
+// @Param active query bool false "Only active or only inactive punishments"
+// @Param limit query int false "Max number of entries"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {array} m.Ban
+// @Failure 400 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/bans [get]
+func (a *BanAPI) ListBans(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := m.BanFilter{
+		SteamID: query.Get("steam_id"),
+		Type:    m.BanType(query.Get("type")),
+	}
+
+	if v := query.Get("active"); v != "" {
+		active, err := strconv.ParseBool(v)
+		if err != nil {
+			a.logger.WithContext(r.Context()).Errorln(err)
+			render.Error(w, http.StatusBadRequest, ErrInvalidQuery)
+
+			return
+		}
+
+		filter.Active = &active
+	}
+
+	filter.Limit, _ = strconv.Atoi(query.Get("limit"))
+	filter.Offset, _ = strconv.Atoi(query.Get("offset"))
+
+	bans, err := a.service.ListBans(r.Context(), filter)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	// IP addresses are not for the public.
+	for i := range bans {
+		bans[i].IP = ""
+	}
+
+	respond(w, r, http.StatusOK, bans)
+}
+
+// @Summary Bans, mutes or gags player
+// @Description Available to admins and to game servers with a bans:write key.
+// @Tags bans
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Accept json
+// @Produce json
+// @Param ban body m.BanInput true "Punishment"
+// @Success 201 {object} m.Ban
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/bans [post]
+func (a *BanAPI) CreateBan(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := actorID(r.Context())
+	if !ok {
+		a.logger.WithContext(r.Context()).Errorln(ErrUnauthorized)
+		render.Error(w, http.StatusUnauthorized, ErrUnauthorized)
+
+		return
+	}
+
+	var input m.BanInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidBody)
+
+		return
+	}
+
+	ban, err := a.service.CreateBan(r.Context(), adminID, input)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusCreated, ban)
+}
+
+// @Summary Lifts ban, mute or gag
+// @Description Available to admins and to game servers with a bans:write key.
+// @Tags bans
+// @Security BearerAuth
+// @Security APIKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Punishment ID"
+// @Param lift body m.BanLift true "Lift reason"
+// @Success 200 {object} m.Ban
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 404 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/bans/{id}/lift [post]
+func (a *BanAPI) LiftBan(w http.ResponseWriter, r *http.Request) {
+	adminID, ok := actorID(r.Context())
+	if !ok {
+		a.logger.WithContext(r.Context()).Errorln(ErrUnauthorized)
+		render.Error(w, http.StatusUnauthorized, ErrUnauthorized)
+
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidID)
+
+		return
+	}
+
+	var lift m.BanLift
+	if err := json.NewDecoder(r.Body).Decode(&lift); err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidBody)
+
+		return
+	}
+
+	ban, err := a.service.LiftBan(r.Context(), id, adminID, lift.Reason)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusOK, ban)
+}
+
+// @Summary Checks connecting player for active punishments
+// @Description Called by game servers, authenticated with a server API key.
+// @Tags bans
+// @Security APIKeyAuth
+// @Produce json
+// @Param steam_id query string true "Player SteamID"
+// @Param ip query string false "Player IP address"
+// @Success 200 {object} m.BanCheck
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/bans/check [get]
+func (a *BanAPI) CheckPlayer(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	check, err := a.service.CheckPlayer(r.Context(), query.Get("steam_id"), query.Get("ip"))
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusOK, check)
+}
@@ -0,0 +1,297 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cs2-server/backend/internal/events"
+	"github.com/cs2-server/backend/internal/middleware"
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ErrInvalidTopic = "invalid topic"
+
+	wsWriteTimeout = 10 * time.Second
+	wsMaxMessage   = 4096
+)
+
+type eventHub interface {
+	Publish(m.Event)
+	Subscribe([]m.Topic) *events.Subscription
+	Unsubscribe(*events.Subscription)
+}
+
+// wsMessage is what WebSocket clients send to change their subscription.
+type wsMessage struct {
+	Action string    `json:"action"`
+	Topics []m.Topic `json:"topics"`
+}
+
+type EventAPI struct {
+	logger    *logrus.Logger
+	hub       eventHub
+	heartbeat time.Duration
+	upgrader  websocket.Upgrader
+}
+
+// NewEventAPI accepts WebSocket connections from pages on origins that
+// allowOrigin accepts. Clients that send no Origin header are not browsers
+// and are always accepted.
+func NewEventAPI(logger *logrus.Logger, hub eventHub, heartbeat time.Duration, allowOrigin func(string) bool) *EventAPI {
+	return &EventAPI{
+		logger:    logger,
+		hub:       hub,
+		heartbeat: heartbeat,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+
+				return origin == "" || allowOrigin(origin)
+			},
+		},
+	}
+}
+
+// @Summary Streams live events
+// @Description Server-sent events by default, WebSocket when the request is an upgrade.
+// @Description The token may be passed in the access_token query parameter.
+// @Description WebSocket clients can send {"action":"subscribe","topics":[...]} to change topics.
+// @Tags events
+// @Security BearerAuth
+// @Produce text/event-stream
+// @Param topics query string false "Comma separated topics: kills, servers, matches, bans. All by default"
+// @Param access_token query string false "Access token"
+// @Success 200 {object} m.Event
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Router /api/v1/events [get]
+func (a *EventAPI) Stream(w http.ResponseWriter, r *http.Request) {
+	topics, err := parseTopics(r.URL.Query().Get("topics"))
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidTopic)
+
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(r) {
+		a.streamWebSocket(w, r, topics)
+
+		return
+	}
+
+	a.streamSSE(w, r, topics)
+}
+
+// @Summary Publishes game event
+// @Description Called by game servers, authenticated with an events:publish key.
+// @Tags events
+// @Security APIKeyAuth
+// @Accept json
+// @Produce json
+// @Param event body m.GameEventInput true "Event"
+// @Success 202 {object} m.Event
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Router /api/v1/events [post]
+func (a *EventAPI) Publish(w http.ResponseWriter, r *http.Request) {
+	key, ok := middleware.APIKey(r.Context())
+	if !ok {
+		a.logger.WithContext(r.Context()).Errorln(ErrUnauthorized)
+		render.Error(w, http.StatusUnauthorized, ErrUnauthorized)
+
+		return
+	}
+
+	var input m.GameEventInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidBody)
+
+		return
+	}
+
+	ev, err := input.Event(key.ServerID)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	a.hub.Publish(ev)
+
+	respond(w, r, http.StatusAccepted, ev)
+}
+
+func (a *EventAPI) streamSSE(w http.ResponseWriter, r *http.Request, topics []m.Topic) {
+	rc := http.NewResponseController(w)
+
+	// The stream outlives the server write timeout, deadlines are set per write.
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		a.logger.WithContext(r.Context()).Warnln("sse write deadline:", err)
+	}
+
+	sub := a.hub.Subscribe(topics)
+	defer a.hub.Unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	write := func(s string) bool {
+		rc.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+
+		if _, err := fmt.Fprint(w, s); err != nil {
+			return false
+		}
+
+		return rc.Flush() == nil
+	}
+
+	if !write(": connected\n\n") {
+		return
+	}
+
+	ticker := time.NewTicker(a.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if !write(": heartbeat\n\n") {
+				return
+			}
+		case ev, ok := <-sub.C:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(ev)
+			if err != nil {
+				a.logger.WithContext(r.Context()).Errorln(err)
+
+				continue
+			}
+
+			if !write(fmt.Sprintf("event: %s\ndata: %s\n\n", ev.Type, data)) {
+				return
+			}
+		}
+	}
+}
+
+func (a *EventAPI) streamWebSocket(w http.ResponseWriter, r *http.Request, topics []m.Topic) {
+	conn, err := a.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// The upgrader has already written the error response.
+		a.logger.WithContext(r.Context()).Errorln(err)
+
+		return
+	}
+	defer conn.Close()
+
+	sub := a.hub.Subscribe(topics)
+	defer a.hub.Unsubscribe(sub)
+
+	done := make(chan struct{})
+	go a.writeWebSocket(conn, sub, done)
+
+	conn.SetReadLimit(wsMaxMessage)
+	conn.SetReadDeadline(time.Now().Add(2 * a.heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * a.heartbeat))
+	})
+
+	for {
+		var msg wsMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			break
+		}
+
+		if msg.Action != "subscribe" {
+			continue
+		}
+
+		if err := validateTopics(msg.Topics); err != nil {
+			a.logger.WithContext(r.Context()).Warnln(err)
+
+			continue
+		}
+
+		sub.SetTopics(msg.Topics)
+	}
+
+	close(done)
+}
+
+func (a *EventAPI) writeWebSocket(conn *websocket.Conn, sub *events.Subscription, done <-chan struct{}) {
+	ticker := time.NewTicker(a.heartbeat)
+	defer ticker.Stop()
+
+	// Closing the connection unblocks the reader in streamWebSocket.
+	defer conn.Close()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+				return
+			}
+		case ev, ok := <-sub.C:
+			if !ok {
+				msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server is shutting down")
+				conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(wsWriteTimeout))
+
+				return
+			}
+
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+
+			if err := conn.WriteJSON(ev); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func parseTopics(s string) ([]m.Topic, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	var topics []m.Topic
+	for _, t := range strings.Split(s, ",") {
+		topics = append(topics, m.Topic(strings.TrimSpace(t)))
+	}
+
+	if err := validateTopics(topics); err != nil {
+		return nil, err
+	}
+
+	return topics, nil
+}
+
+func validateTopics(topics []m.Topic) error {
+	for _, t := range topics {
+		if !t.Valid() {
+			return fmt.Errorf("unknown topic %q", t)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cs2-server/backend/internal/events"
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+func TestEventStreamSSEShutdown(t *testing.T) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	hub := events.NewHub(8)
+	api := NewEventAPI(logger, hub, time.Minute, func(string) bool { return true })
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(api.Stream))
+	srv.Config.RegisterOnShutdown(hub.Close)
+	srv.Start()
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "?topics=bans")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("got content type %q", ct)
+	}
+
+	r := bufio.NewReader(resp.Body)
+	if line, _ := r.ReadString('\n'); line != ": connected\n" {
+		t.Fatalf("got first line %q", line)
+	}
+
+	hub.Publish(m.Event{Topic: m.TopicKills, Type: m.EventKill})
+	hub.Publish(m.Event{Topic: m.TopicBans, Type: m.EventBanCreated})
+
+	r.ReadString('\n')
+	if line, _ := r.ReadString('\n'); line != "event: ban_created\n" {
+		t.Fatalf("got event line %q", line)
+	}
+
+	if line, _ := r.ReadString('\n'); !strings.HasPrefix(line, `data: {"topic":"bans"`) {
+		t.Fatalf("got data line %q", line)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := srv.Config.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown with open stream: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/sirupsen/logrus"
+)
+
+type healthService interface {
+	Ready(context.Context) m.Readiness
+}
+
+type HealthAPI struct {
+	logger  *logrus.Logger
+	service healthService
+}
+
+func NewHealthAPI(logger *logrus.Logger, service healthService) *HealthAPI {
+	return &HealthAPI{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// @Summary Reports that the process is up
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Router /healthz [get]
+func (a *HealthAPI) Healthz(w http.ResponseWriter, r *http.Request) {
+	render.JSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// @Summary Reports whether the server can take traffic
+// @Description Fails while draining before shutdown, when Postgres is down,
+// @Description when migrations are missing and, if enabled, when Steam is unreachable.
+// @Tags health
+// @Produce json
+// @Success 200 {object} m.Readiness
+// @Failure 503 {object} m.Readiness
+// @Router /readyz [get]
+func (a *HealthAPI) Readyz(w http.ResponseWriter, r *http.Request) {
+	res := a.service.Ready(r.Context())
+
+	if !res.Ready {
+		for _, c := range res.Checks {
+			if !c.OK {
+				a.logger.WithContext(r.Context()).WithField("check", c.Name).Warnln(c.Err)
+			}
+		}
+
+		a.logger.WithContext(r.Context()).WithField("draining", res.Draining).Warnln("not ready")
+		render.JSON(w, http.StatusServiceUnavailable, res)
+
+		return
+	}
+
+	render.JSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+type fakeHealthService struct {
+	res m.Readiness
+}
+
+func (f fakeHealthService) Ready(context.Context) m.Readiness {
+	return f.res
+}
+
+func TestReadyzHidesErrors(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+
+	a := NewHealthAPI(logger, fakeHealthService{res: m.Readiness{
+		Checks: []m.HealthCheck{
+			{Name: "postgres", Err: errors.New("dial tcp 10.0.0.5:5432: connection refused")},
+			{Name: "migrations", OK: true},
+		},
+	}})
+
+	w := httptest.NewRecorder()
+	a.Readyz(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+
+	want := `{"ready":false,"draining":false,"checks":[{"name":"postgres","ok":false},{"name":"migrations","ok":true}]}`
+	if body := strings.TrimSpace(w.Body.String()); body != want {
+		t.Errorf("body %s, want %s", body, want)
+	}
+
+	entry := hook.AllEntries()[0]
+	if entry.Data["check"] != "postgres" || !strings.Contains(entry.Message, "10.0.0.5") {
+		t.Errorf("error not logged: %v %q", entry.Data, entry.Message)
+	}
+}
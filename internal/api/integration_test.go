@@ -0,0 +1,226 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cs2-server/backend/config"
+	"github.com/cs2-server/backend/internal/breaker"
+	"github.com/cs2-server/backend/internal/events"
+	"github.com/cs2-server/backend/internal/middleware"
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/router"
+	"github.com/cs2-server/backend/internal/service"
+	"github.com/cs2-server/backend/internal/service/steamtest"
+	"github.com/cs2-server/backend/internal/storage"
+	"github.com/cs2-server/backend/internal/storage/storagetest"
+	"github.com/cs2-server/backend/pkg/a2s"
+	"github.com/cs2-server/backend/pkg/jwt"
+	"github.com/sirupsen/logrus"
+)
+
+const testJWTKey = "integration-test-key-of-32-bytes!"
+
+// newTestAPI serves the v1 routes the way main wires them, against a fresh
+// schema with the fixtures loaded and a stubbed Steam.
+func newTestAPI(t *testing.T) (*httptest.Server, *jwt.JWT) {
+	t.Helper()
+
+	db := storagetest.New(t)
+	storagetest.Load(t, db)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	cfg := &config.Config{}
+	cfg.Steam.APIKey = steamtest.Key
+
+	steam := steamtest.NewServer(t)
+	hub := events.NewHub(8)
+	t.Cleanup(hub.Close)
+
+	tokens := jwt.New(testJWTKey, nil, nil)
+
+	authStorage := storage.NewAuthStorage(db, time.Second, breaker.New(5, time.Minute))
+	adminStorage := storage.NewAdminStorage(db)
+	serverStorage := storage.NewServerStorage(db)
+
+	serverService := service.NewServerService(serverStorage, a2s.NewClient(time.Second), hub, logger)
+	rconService := service.NewRCONService(serverStorage, adminStorage, storage.NewRCONStorage(db), time.Second, logger)
+	t.Cleanup(rconService.Close)
+
+	apiKeyService := service.NewAPIKeyService(storage.NewAPIKeyStorage(db), serverStorage)
+
+	handlers := Handlers{
+		Auth:    NewAuthAPI(cfg, logger, tokens, service.NewAuthService(authStorage, steam.URL, noopSteam{})),
+		Servers: NewServerAPI(logger, serverService),
+		RCON:    NewRCONAPI(logger, rconService),
+		APIKeys: NewAPIKeyAPI(logger, apiKeyService),
+		Bans:    NewBanAPI(logger, service.NewBanService(storage.NewBanStorage(db), hub)),
+		Events:  NewEventAPI(logger, hub, time.Minute, func(string) bool { return true }),
+		Maps:    NewMapAPI(logger, service.NewMapService(storage.NewMapStorage(db), hub)),
+	}
+
+	guards := Guards{
+		JWT:         tokens,
+		Permissions: middleware.NewPermissions(service.NewAdminService(adminStorage), logger),
+		APIKeys:     middleware.NewAPIKeys(apiKeyService, logger),
+		AccessLog:   middleware.NewAccessLog(logger),
+	}
+
+	routes := router.New()
+	Register(routes.Group(V1.Prefix(), V1.Handler), handlers, guards)
+
+	srv := httptest.NewServer(routes)
+	t.Cleanup(srv.Close)
+
+	return srv, tokens
+}
+
+type noopSteam struct{}
+
+func (noopSteam) ObserveSteam(string, time.Duration, error) {}
+
+func TestRoutesIntegration(t *testing.T) {
+	srv, tokens := newTestAPI(t)
+
+	issue := func(steamID string) m.JWT {
+		t.Helper()
+
+		pair, err := tokens.GenerateTokens(steamID)
+		if err != nil {
+			t.Fatalf("GenerateTokens: %v", err)
+		}
+
+		return pair
+	}
+
+	bearer := func(steamID string) string {
+		return "Bearer " + issue(steamID).AccessToken
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		auth   string
+		body   string
+		form   url.Values
+		status int
+		want   string
+	}{
+		{
+			name: "profile without token", method: http.MethodGet,
+			path: "/profile/" + storagetest.PlayerID, status: http.StatusUnauthorized,
+		},
+		{
+			name: "profile", method: http.MethodGet, path: "/profile/" + storagetest.PlayerID,
+			auth: bearer(storagetest.PlayerID), status: http.StatusOK, want: `"kills":120`,
+		},
+		{
+			name: "refresh", method: http.MethodPost, path: "/auth/refresh",
+			auth: "Bearer " + issue(storagetest.PlayerID).RefreshToken, form: url.Values{"id": {storagetest.PlayerID}},
+			status: http.StatusOK, want: `"refresh_token"`,
+		},
+		{
+			name: "refresh with access token", method: http.MethodPost, path: "/auth/refresh",
+			auth: bearer(storagetest.PlayerID), status: http.StatusUnauthorized,
+		},
+		{
+			name: "admin route with refresh token", method: http.MethodPost, path: "/servers",
+			auth: "Bearer " + issue(storagetest.AdminID).RefreshToken, body: `{"name":"Inferno","host":"127.0.0.1","port":27017}`,
+			status: http.StatusUnauthorized,
+		},
+		{
+			name: "refresh for another player", method: http.MethodPost, path: "/auth/refresh",
+			auth: "Bearer " + issue(storagetest.PlayerID).RefreshToken, form: url.Values{"id": {storagetest.AdminID}},
+			status: http.StatusForbidden,
+		},
+		{
+			name: "servers", method: http.MethodGet, path: "/servers",
+			status: http.StatusOK, want: `"Dust II"`,
+		},
+		{
+			name: "create server as player", method: http.MethodPost, path: "/servers",
+			auth: bearer(storagetest.PlayerID), body: `{"name":"Mirage","host":"127.0.0.1","port":27016}`,
+			status: http.StatusForbidden,
+		},
+		{
+			name: "create server as admin", method: http.MethodPost, path: "/servers",
+			auth: bearer(storagetest.AdminID), body: `{"name":"Mirage","host":"127.0.0.1","port":27016}`,
+			status: http.StatusCreated, want: `"Mirage"`,
+		},
+		{
+			name: "bans", method: http.MethodGet, path: "/bans",
+			status: http.StatusOK, want: storagetest.BannedID,
+		},
+		{
+			name: "maps", method: http.MethodGet, path: "/maps",
+			status: http.StatusOK, want: `"ct_win_rate":50`,
+		},
+		{
+			name: "leaderboard", method: http.MethodGet, path: "/maps/" + storagetest.PlayedMap + "/leaderboard",
+			status: http.StatusOK, want: `"rank":1,"steam_id":"` + storagetest.PlayerID + `"`,
+		},
+		{
+			name: "leaderboard of unplayed map", method: http.MethodGet, path: "/maps/de_vertigo/leaderboard",
+			status: http.StatusNotFound,
+		},
+		{
+			name: "round without key", method: http.MethodPost, path: "/rounds",
+			body: `{"map":"de_dust2","winner":"ct","duration":90}`, status: http.StatusUnauthorized,
+		},
+		{
+			name: "ban check without key", method: http.MethodGet, path: "/bans/check?steam_id=" + storagetest.BannedID,
+			status: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body io.Reader
+			if tt.body != "" {
+				body = strings.NewReader(tt.body)
+			} else if tt.form != nil {
+				body = strings.NewReader(tt.form.Encode())
+			}
+
+			req, err := http.NewRequest(tt.method, srv.URL+V1.Prefix()+tt.path, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			switch {
+			case tt.body != "":
+				req.Header.Set("Content-Type", "application/json")
+			case tt.form != nil:
+				req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			}
+
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			data, _ := io.ReadAll(resp.Body)
+
+			if resp.StatusCode != tt.status {
+				t.Fatalf("got %d, want %d: %s", resp.StatusCode, tt.status, data)
+			}
+
+			if !strings.Contains(string(data), tt.want) {
+				t.Fatalf("body %s does not contain %s", data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/cs2-server/backend/internal/middleware"
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/sirupsen/logrus"
+)
+
+type mapService interface {
+	RecordRound(context.Context, int64, m.RoundInput) (m.Round, error)
+	ListMaps(context.Context) ([]m.MapStats, error)
+	Leaderboard(context.Context, string, int) ([]m.LeaderboardEntry, error)
+}
+
+type MapAPI struct {
+	logger  *logrus.Logger
+	service mapService
+}
+
+func NewMapAPI(logger *logrus.Logger, service mapService) *MapAPI {
+	return &MapAPI{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// @Summary Lists played maps with round counts, win rates by side and average round length
+// @Tags maps
+// @Produce json
+// @Success 200 {array} m.MapStats
+// @Failure 500 {object} render.Err
+// @Router /api/v1/maps [get]
+func (a *MapAPI) ListMaps(w http.ResponseWriter, r *http.Request) {
+	maps, err := a.service.ListMaps(r.Context())
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	respond(w, r, http.StatusOK, maps)
+}
+
+// @Summary Ranks the players of a map by kills
+// @Tags maps
+// @Produce json
+// @Param name path string true "Map name"
+// @Param limit query int false "Max number of entries"
+// @Success 200 {array} m.LeaderboardEntry
+// @Failure 400 {object} render.Err
+// @Failure 404 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/maps/{name}/leaderboard [get]
+func (a *MapAPI) Leaderboard(w http.ResponseWriter, r *http.Request) {
+	var limit int
+
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil {
+			a.logger.WithContext(r.Context()).Errorln(err)
+			render.Error(w, http.StatusBadRequest, ErrInvalidQuery)
+
+			return
+		}
+	}
+
+	entries, err := a.service.Leaderboard(r.Context(), r.PathValue("name"), limit)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusOK, entries)
+}
+
+// @Summary Records a finished round
+// @Description Called by game servers, authenticated with an events:publish key.
+// @Tags maps
+// @Security APIKeyAuth
+// @Accept json
+// @Produce json
+// @Param round body m.RoundInput true "Round"
+// @Success 201 {object} m.Round
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/rounds [post]
+func (a *MapAPI) RecordRound(w http.ResponseWriter, r *http.Request) {
+	key, ok := middleware.APIKey(r.Context())
+	if !ok {
+		a.logger.WithContext(r.Context()).Errorln(ErrUnauthorized)
+		render.Error(w, http.StatusUnauthorized, ErrUnauthorized)
+
+		return
+	}
+
+	var input m.RoundInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidBody)
+
+		return
+	}
+
+	round, err := a.service.RecordRound(r.Context(), key.ServerID, input)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusCreated, round)
+}
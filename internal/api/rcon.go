@@ -0,0 +1,118 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/cs2-server/backend/pkg/jwt"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ErrUnauthorized = "unauthorized"
+)
+
+type rconService interface {
+	Execute(context.Context, string, int64, string) (string, error)
+	ListLogs(context.Context, int64, int) ([]m.RCONLog, error)
+}
+
+type RCONAPI struct {
+	logger  *logrus.Logger
+	service rconService
+}
+
+func NewRCONAPI(logger *logrus.Logger, service rconService) *RCONAPI {
+	return &RCONAPI{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// @Summary Runs RCON command on game server
+// @Tags rcon
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Server ID"
+// @Param command body m.RCONRequest true "Command"
+// @Success 200 {object} m.RCONResponse
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 404 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/servers/{id}/rcon [post]
+func (a *RCONAPI) Execute(w http.ResponseWriter, r *http.Request) {
+	steamID, ok := jwt.SteamID(r.Context())
+	if !ok {
+		a.logger.WithContext(r.Context()).Errorln(ErrUnauthorized)
+		render.Error(w, http.StatusUnauthorized, ErrUnauthorized)
+
+		return
+	}
+
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidID)
+
+		return
+	}
+
+	var req m.RCONRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Command == "" {
+		a.logger.WithContext(r.Context()).Errorln(ErrInvalidBody)
+		render.Error(w, http.StatusBadRequest, ErrInvalidBody)
+
+		return
+	}
+
+	out, err := a.service.Execute(r.Context(), steamID, id, req.Command)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusOK, m.RCONResponse{Output: out})
+}
+
+// @Summary Lists RCON commands run on game server
+// @Tags rcon
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Server ID"
+// @Param limit query int false "Max number of entries"
+// @Success 200 {array} m.RCONLog
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/servers/{id}/rcon/logs [get]
+func (a *RCONAPI) ListLogs(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidID)
+
+		return
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	logs, err := a.service.ListLogs(r.Context(), id, limit)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusOK, logs)
+}
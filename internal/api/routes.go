@@ -0,0 +1,86 @@
+package api
+
+import (
+	"github.com/cs2-server/backend/internal/middleware"
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/router"
+	"github.com/cs2-server/backend/pkg/jwt"
+)
+
+// Handlers are the API endpoints Register routes to.
+type Handlers struct {
+	Auth    *AuthAPI
+	Servers *ServerAPI
+	RCON    *RCONAPI
+	APIKeys *APIKeyAPI
+	Bans    *BanAPI
+	Events  *EventAPI
+	Maps    *MapAPI
+}
+
+// Guards are the middlewares routes are composed of. Nil rate limits are
+// not applied.
+type Guards struct {
+	JWT         *jwt.JWT
+	Permissions *middleware.Permissions
+	APIKeys     *middleware.APIKeys
+	AccessLog   *middleware.AccessLog
+
+	LoginLimit   router.Middleware
+	RefreshLimit router.Middleware
+	ProfileLimit router.Middleware
+	AdminLimit   router.Middleware
+}
+
+// Register adds the API routes to g, which carries the version prefix.
+func Register(g *router.Group, h Handlers, mw Guards) {
+	var (
+		log          = mw.AccessLog.Log
+		liveCache    = middleware.Cache(middleware.CacheLive)
+		publicCache  = middleware.Cache(middleware.CachePublic)
+		privateCache = middleware.Cache(middleware.CachePrivate)
+	)
+
+	player := g.With(mw.JWT.Auth)
+	admin := player.With(mw.AdminLimit)
+
+	auth := g.Group("/auth")
+	auth.Get("/login", h.Auth.Login, mw.LoginLimit, log)
+	auth.Get("/process", h.Auth.ProcessLogin, mw.LoginLimit, log)
+	auth.With(mw.JWT.Refresh).Post("/refresh", h.Auth.RefreshToken, mw.RefreshLimit, log)
+
+	player.Get("/profile/{id}", h.Auth.GetProfile, mw.ProfileLimit, log, privateCache)
+
+	g.Get("/servers", h.Servers.ListServers, log, liveCache)
+	g.Get("/servers/{id}", h.Servers.GetServer, log, liveCache)
+
+	manageServers := admin.With(mw.Permissions.Require(m.PermManageServers), log)
+	manageServers.Post("/servers", h.Servers.CreateServer)
+	manageServers.Put("/servers/{id}", h.Servers.UpdateServer)
+	manageServers.Delete("/servers/{id}", h.Servers.DeleteServer)
+
+	manageServers.Get("/servers/{id}/keys", h.APIKeys.List)
+	manageServers.Post("/servers/{id}/keys", h.APIKeys.Issue)
+	manageServers.Post("/servers/{id}/keys/{keyID}/rotate", h.APIKeys.Rotate)
+	manageServers.Delete("/servers/{id}/keys/{keyID}", h.APIKeys.Revoke)
+
+	rcon := admin.With(mw.Permissions.Require(m.PermRCON), log)
+	rcon.Post("/servers/{id}/rcon", h.RCON.Execute)
+	rcon.Get("/servers/{id}/rcon/logs", h.RCON.ListLogs)
+
+	// Ban writes come from game servers with a key or from admins.
+	banAdmin := router.Chain(mw.JWT.Auth, mw.AdminLimit, mw.Permissions.Require(m.PermManageBans))
+	banWrites := g.With(mw.APIKeys.AuthOr(m.ScopeBansWrite, banAdmin), log)
+
+	g.Get("/bans", h.Bans.ListBans, log, publicCache)
+	banWrites.Post("/bans", h.Bans.CreateBan)
+	banWrites.Post("/bans/{id}/lift", h.Bans.LiftBan)
+	g.Get("/bans/check", h.Bans.CheckPlayer, mw.APIKeys.Auth(m.ScopeBansCheck), log)
+
+	g.Get("/events", h.Events.Stream, mw.JWT.AuthQuery, log)
+	g.Post("/events", h.Events.Publish, mw.APIKeys.Auth(m.ScopeEventsPublish), log)
+
+	g.Get("/maps", h.Maps.ListMaps, log, publicCache)
+	g.Get("/maps/{name}/leaderboard", h.Maps.Leaderboard, log, publicCache)
+	g.Post("/rounds", h.Maps.RecordRound, mw.APIKeys.Auth(m.ScopeEventsPublish), log)
+}
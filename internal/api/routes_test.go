@@ -0,0 +1,49 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cs2-server/backend/docs"
+	"github.com/cs2-server/backend/internal/router"
+)
+
+// TestRoutesMatchSwagger keeps the route table and the swagger annotations
+// in step: every v1 route is documented and every documented path is routed.
+func TestRoutesMatchSwagger(t *testing.T) {
+	r := router.New()
+	Register(r.Group(V1.Prefix()), Handlers{}, Guards{})
+
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+
+	if err := json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), &spec); err != nil {
+		t.Fatalf("parse swagger: %v", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, ops := range spec.Paths {
+		if !strings.HasPrefix(path, V1.Prefix()) {
+			continue
+		}
+
+		for method := range ops {
+			documented[strings.ToUpper(method)+" "+path] = true
+		}
+	}
+
+	for _, route := range r.Routes() {
+		key := route.Method + " " + route.Path
+		if !documented[key] {
+			t.Errorf("%s has no swagger annotation", key)
+		}
+
+		delete(documented, key)
+	}
+
+	for key := range documented {
+		t.Errorf("%s is documented but not routed", key)
+	}
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ErrInvalidID    = "invalid id"
+	ErrInvalidBody  = "invalid request body"
+	ErrInvalidQuery = "invalid query"
+	ErrNotFound     = "not found"
+)
+
+type serverService interface {
+	ListServers(context.Context) ([]m.ServerInfo, error)
+	GetServer(context.Context, int64) (m.ServerInfo, error)
+	CreateServer(context.Context, m.ServerInput) (m.Server, error)
+	UpdateServer(context.Context, int64, m.ServerInput) (m.Server, error)
+	DeleteServer(context.Context, int64) error
+}
+
+type ServerAPI struct {
+	logger  *logrus.Logger
+	service serverService
+}
+
+func NewServerAPI(logger *logrus.Logger, service serverService) *ServerAPI {
+	return &ServerAPI{
+		logger:  logger,
+		service: service,
+	}
+}
+
+// @Summary Lists game servers with their live status
+// @Tags servers
+// @Produce json
+// @Success 200 {array} m.ServerInfo
+// @Failure 500 {object} render.Err
+// @Router /api/v1/servers [get]
+func (a *ServerAPI) ListServers(w http.ResponseWriter, r *http.Request) {
+	servers, err := a.service.ListServers(r.Context())
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusInternalServerError, err.Error())
+
+		return
+	}
+
+	respond(w, r, http.StatusOK, servers)
+}
+
+// @Summary Retrieves game server with its live status
+// @Tags servers
+// @Produce json
+// @Param id path int true "Server ID"
+// @Success 200 {object} m.ServerInfo
+// @Failure 400 {object} render.Err
+// @Failure 404 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/servers/{id} [get]
+func (a *ServerAPI) GetServer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidID)
+
+		return
+	}
+
+	server, err := a.service.GetServer(r.Context(), id)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusOK, server)
+}
+
+// @Summary Registers game server
+// @Tags servers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param server body m.ServerInput true "Server"
+// @Success 201 {object} m.Server
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/servers [post]
+func (a *ServerAPI) CreateServer(w http.ResponseWriter, r *http.Request) {
+	var input m.ServerInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidBody)
+
+		return
+	}
+
+	server, err := a.service.CreateServer(r.Context(), input)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusCreated, server)
+}
+
+// @Summary Updates game server
+// @Tags servers
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Server ID"
+// @Param server body m.ServerInput true "Server"
+// @Success 200 {object} m.Server
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 404 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/servers/{id} [put]
+func (a *ServerAPI) UpdateServer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidID)
+
+		return
+	}
+
+	var input m.ServerInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidBody)
+
+		return
+	}
+
+	server, err := a.service.UpdateServer(r.Context(), id, input)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusOK, server)
+}
+
+// @Summary Deletes game server
+// @Tags servers
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "Server ID"
+// @Success 204 {object} nil
+// @Failure 400 {object} render.Err
+// @Failure 401 {object} render.Err
+// @Failure 403 {object} render.Err
+// @Failure 404 {object} render.Err
+// @Failure 500 {object} render.Err
+// @Router /api/v1/servers/{id} [delete]
+func (a *ServerAPI) DeleteServer(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		render.Error(w, http.StatusBadRequest, ErrInvalidID)
+
+		return
+	}
+
+	if err := a.service.DeleteServer(r.Context(), id); err != nil {
+		a.logger.WithContext(r.Context()).Errorln(err)
+		renderServiceError(w, err)
+
+		return
+	}
+
+	respond(w, r, http.StatusNoContent, nil)
+}
@@ -1,8 +1,15 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
+
+	"github.com/cs2-server/backend/internal/middleware"
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/cs2-server/backend/pkg/jwt"
 )
 
 func JSON(w http.ResponseWriter, status int, data any) {
@@ -15,3 +22,31 @@ func JSON(w http.ResponseWriter, status int, data any) {
 		}
 	}
 }
+
+// renderServiceError maps errors returned by services to response statuses.
+func renderServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, m.ErrNotFound):
+		render.Error(w, http.StatusNotFound, ErrNotFound)
+	case errors.Is(err, m.ErrInvalidInput):
+		render.Error(w, http.StatusBadRequest, err.Error())
+	case errors.Is(err, m.ErrForbidden):
+		render.Error(w, http.StatusForbidden, err.Error())
+	default:
+		render.Error(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// actorID identifies who is making the request: the SteamID of an
+// authenticated player or the server behind an API key.
+func actorID(ctx context.Context) (string, bool) {
+	if id, ok := jwt.SteamID(ctx); ok {
+		return id, true
+	}
+
+	if key, ok := middleware.APIKey(ctx); ok {
+		return key.Actor(), true
+	}
+
+	return "", false
+}
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cs2-server/backend/internal/render"
+)
+
+type versionCtxKey struct{}
+
+// Version is an API version mounted under /api/<name>. Versions share the
+// handlers; the models handlers respond with are the v1 ones, and a version
+// that changes a response shape converts them in Present.
+type Version struct {
+	Name string
+	// Present maps a v1 response model to this version's, or returns it
+	// unchanged. Nil keeps every model as it is.
+	Present func(any) any
+}
+
+var V1 = &Version{Name: "v1"}
+
+func (v *Version) Prefix() string {
+	return "/api/" + v.Name
+}
+
+// Handler marks requests to next as made against v.
+func (v *Version) Handler(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), versionCtxKey{}, v)))
+	})
+}
+
+// respond renders data as JSON in the shape of the version the request was
+// made against.
+func respond(w http.ResponseWriter, r *http.Request, status int, data any) {
+	if v, ok := r.Context().Value(versionCtxKey{}).(*Version); ok && v.Present != nil {
+		data = v.Present(data)
+	}
+
+	render.JSON(w, status, data)
+}
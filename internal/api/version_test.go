@@ -0,0 +1,48 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "github.com/cs2-server/backend/internal/model"
+)
+
+func TestVersionPresent(t *testing.T) {
+	type profileV2 struct {
+		SteamID string `json:"steam_id"`
+	}
+
+	v2 := &Version{Name: "v2", Present: func(data any) any {
+		if p, ok := data.(m.Profile); ok {
+			return profileV2{SteamID: p.ID}
+		}
+
+		return data
+	}}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		respond(w, r, http.StatusOK, m.Profile{ID: "76561198000000000"})
+	}
+
+	for _, tt := range []struct {
+		version *Version
+		key     string
+	}{
+		{V1, "id"},
+		{v2, "steam_id"},
+	} {
+		w := httptest.NewRecorder()
+		tt.version.Handler(handler)(w, httptest.NewRequest(http.MethodGet, tt.version.Prefix()+"/profile/1", nil))
+
+		var body map[string]any
+		if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+			t.Fatalf("%s: decode: %v", tt.version.Name, err)
+		}
+
+		if body[tt.key] != "76561198000000000" {
+			t.Errorf("%s: body %v has no %q", tt.version.Name, body, tt.key)
+		}
+	}
+}
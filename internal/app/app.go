@@ -0,0 +1,132 @@
+// Package app runs the components of the server in order: started first to
+// last, stopped last to first within one shutdown timeout.
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Hook starts and stops a component. Either function may be nil.
+type Hook struct {
+	Name  string
+	Start func(ctx context.Context) error
+	Stop  func(ctx context.Context) error
+}
+
+type App struct {
+	logger          *logrus.Logger
+	shutdownTimeout time.Duration
+
+	hooks   []Hook
+	started int
+
+	failOnce sync.Once
+	failed   chan error
+}
+
+func New(logger *logrus.Logger, shutdownTimeout time.Duration) *App {
+	return &App{
+		logger:          logger,
+		shutdownTimeout: shutdownTimeout,
+		failed:          make(chan error, 1),
+	}
+}
+
+// Append adds a hook, which starts after and stops before those added
+// earlier.
+func (a *App) Append(h Hook) {
+	a.hooks = append(a.hooks, h)
+}
+
+// Go adds a background worker. run is started in its own goroutine and must
+// return once ctx is done, which Stop waits for.
+func (a *App) Go(name string, run func(ctx context.Context)) {
+	var (
+		cancel context.CancelFunc
+		done   = make(chan struct{})
+	)
+
+	a.Append(Hook{
+		Name: name,
+		Start: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+
+			go func() {
+				defer close(done)
+				run(ctx)
+			}()
+
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return fmt.Errorf("did not stop: %w", ctx.Err())
+			}
+		},
+	})
+}
+
+// Fail reports an error a component cannot recover from, such as the HTTP
+// server failing to serve. Only the first one is kept.
+func (a *App) Fail(err error) {
+	a.failOnce.Do(func() {
+		a.failed <- err
+	})
+}
+
+// Failed receives the error passed to Fail.
+func (a *App) Failed() <-chan error {
+	return a.failed
+}
+
+// Start runs the Start hooks in order. If one fails, the components already
+// started are stopped again.
+func (a *App) Start(ctx context.Context) error {
+	for _, h := range a.hooks {
+		if h.Start != nil {
+			if err := h.Start(ctx); err != nil {
+				return errors.Join(fmt.Errorf("Start: %s: %w", h.Name, err), a.Stop())
+			}
+		}
+
+		a.started++
+	}
+
+	return nil
+}
+
+// Stop runs the Stop hooks of the started components in reverse order, all
+// within the shutdown timeout, and returns every error they returned.
+func (a *App) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), a.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+
+	for ; a.started > 0; a.started-- {
+		h := a.hooks[a.started-1]
+		if h.Stop == nil {
+			continue
+		}
+
+		a.logger.Debugf("stopping %s", h.Name)
+
+		if err := h.Stop(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("Stop: %s: %w", h.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestAppOrder(t *testing.T) {
+	var calls []string
+
+	hook := func(name string, startErr, stopErr error) Hook {
+		return Hook{
+			Name: name,
+			Start: func(context.Context) error {
+				calls = append(calls, "start "+name)
+
+				return startErr
+			},
+			Stop: func(context.Context) error {
+				calls = append(calls, "stop "+name)
+
+				return stopErr
+			},
+		}
+	}
+
+	a := New(logrus.New(), time.Second)
+	a.Append(hook("db", nil, errors.New("close failed")))
+	a.Append(hook("poller", nil, nil))
+	a.Append(hook("http", nil, errors.New("shutdown failed")))
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	err := a.Stop()
+	if err == nil || !strings.Contains(err.Error(), "db: close failed") || !strings.Contains(err.Error(), "http: shutdown failed") {
+		t.Errorf("errors not aggregated: %v", err)
+	}
+
+	want := "start db, start poller, start http, stop http, stop poller, stop db"
+	if got := strings.Join(calls, ", "); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// A failed start stops only what had started.
+	calls = nil
+
+	a = New(logrus.New(), time.Second)
+	a.Append(hook("db", nil, nil))
+	a.Append(hook("http", errors.New("address in use"), nil))
+	a.Append(hook("never", nil, nil))
+
+	if err := a.Start(context.Background()); err == nil || !strings.Contains(err.Error(), "http: address in use") {
+		t.Errorf("start error: %v", err)
+	}
+
+	want = "start db, start http, stop db"
+	if got := strings.Join(calls, ", "); got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestAppGo(t *testing.T) {
+	stopped := make(chan struct{})
+
+	a := New(logrus.New(), time.Second)
+	a.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		close(stopped)
+	})
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-stopped:
+	default:
+		t.Error("worker still running after Stop")
+	}
+
+	// Workers ignoring ctx are reported once the shutdown timeout passes.
+	a = New(logrus.New(), 10*time.Millisecond)
+	a.Go("stuck", func(context.Context) {
+		time.Sleep(time.Second)
+	})
+
+	if err := a.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Stop(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrOpen = errors.New("circuit breaker is open")
+
+// Breaker stops calls to a dependency after a run of consecutive failures.
+// Once the cooldown has passed a single call is let through: its success
+// closes the breaker again, its failure restarts the cooldown.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func New(threshold int, cooldown time.Duration) *Breaker {
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow returns ErrOpen when the call must not be made. Every allowed call has
+// to be followed by Report or Release.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return nil
+	}
+
+	if b.probing || b.now().Sub(b.openedAt) < b.cooldown {
+		return ErrOpen
+	}
+
+	b.probing = true
+
+	return nil
+}
+
+// Report records the outcome of an allowed call; a nil err is a success.
+func (b *Breaker) Report(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if err == nil {
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openedAt = b.now()
+	}
+}
+
+// Release ends an allowed call that says nothing about the dependency, such
+// as one its caller gave up on, without counting it either way. A probe that
+// is released leaves the breaker open for the next call to probe again.
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+// Open reports whether calls are currently being refused.
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.failures >= b.threshold
+}
@@ -0,0 +1,83 @@
+package breaker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBreaker(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := New(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	errDB := errors.New("db")
+
+	call := func(err error) error {
+		if err := b.Allow(); err != nil {
+			return err
+		}
+		b.Report(err)
+		return err
+	}
+
+	call(errDB)
+	if err := call(nil); err != nil {
+		t.Fatalf("success after one failure: %v", err)
+	}
+
+	call(errDB)
+	call(errDB)
+	if err := call(nil); !errors.Is(err, ErrOpen) {
+		t.Fatalf("after threshold: got %v, want ErrOpen", err)
+	}
+
+	now = now.Add(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe after cooldown: %v", err)
+	}
+	if err := b.Allow(); !errors.Is(err, ErrOpen) {
+		t.Fatalf("second call during probe: got %v, want ErrOpen", err)
+	}
+	b.Report(errDB)
+
+	if err := call(nil); !errors.Is(err, ErrOpen) {
+		t.Fatalf("failed probe must restart cooldown, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	if err := call(nil); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if b.Open() {
+		t.Fatal("successful probe must close the breaker")
+	}
+}
+
+func TestBreakerRelease(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := New(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.Allow()
+	b.Report(errors.New("db"))
+
+	now = now.Add(time.Minute)
+	if err := b.Allow(); err != nil {
+		t.Fatalf("probe after cooldown: %v", err)
+	}
+	b.Release()
+
+	if !b.Open() {
+		t.Fatal("a released probe must not close the breaker")
+	}
+
+	if err := b.Allow(); err != nil {
+		t.Fatalf("a released probe must let the next call probe: %v", err)
+	}
+	b.Report(nil)
+
+	if b.Open() {
+		t.Fatal("successful probe must close the breaker")
+	}
+}
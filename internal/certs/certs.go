@@ -0,0 +1,126 @@
+// Package certs serves a TLS certificate from files that may be replaced
+// while the server runs, as certificate renewal tools do.
+package certs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type Reloader struct {
+	certFile string
+	keyFile  string
+	logger   *logrus.Logger
+
+	cert    atomic.Pointer[tls.Certificate]
+	modTime time.Time
+}
+
+// NewReloader loads the key pair from certFile and keyFile.
+func NewReloader(certFile, keyFile string, logger *logrus.Logger) (*Reloader, error) {
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+		logger:   logger,
+	}
+
+	if _, err := r.Reload(); err != nil {
+		return nil, fmt.Errorf("NewReloader: %w", err)
+	}
+
+	return r, nil
+}
+
+// GetCertificate is meant for tls.Config.GetCertificate.
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// Reload loads the key pair again if either file changed since the last
+// load, reporting whether it did. The certificate in use is kept on error.
+func (r *Reloader) Reload() (bool, error) {
+	modTime, err := r.lastModified()
+	if err != nil {
+		return false, fmt.Errorf("Reload (1): %w", err)
+	}
+
+	if !modTime.After(r.modTime) {
+		return false, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("Reload (2): %w", err)
+	}
+
+	r.cert.Store(&cert)
+	r.modTime = modTime
+
+	return true, nil
+}
+
+// Run checks the files for changes every interval until ctx is done.
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := r.Reload()
+			if err != nil {
+				r.logger.Errorln(err)
+
+				continue
+			}
+
+			if reloaded {
+				r.logger.Infof("tls certificate reloaded from %s", r.certFile)
+			}
+		}
+	}
+}
+
+func (r *Reloader) lastModified() (time.Time, error) {
+	var last time.Time
+
+	for _, name := range []string{r.certFile, r.keyFile} {
+		info, err := os.Stat(name)
+		if err != nil {
+			return time.Time{}, err
+		}
+
+		if info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+
+	return last, nil
+}
+
+// ClientCAs reads the PEM certificates client certificates are verified
+// against.
+func ClientCAs(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("ClientCAs (1): %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(bytes.TrimSpace(pem)) {
+		return nil, errors.New("ClientCAs (2): no certificates found")
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,117 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func writeKeyPair(t *testing.T, certFile, keyFile, name string, modTime time.Time) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for file, block := range map[string]*pem.Block{
+		certFile: {Type: "CERTIFICATE", Bytes: der},
+		keyFile:  {Type: "EC PRIVATE KEY", Bytes: keyDER},
+	} {
+		if err := os.WriteFile(file, pem.EncodeToMemory(block), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.Chtimes(file, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func commonName(t *testing.T, r *Reloader) string {
+	t.Helper()
+
+	cert, _ := r.GetCertificate(nil)
+
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return leaf.Subject.CommonName
+}
+
+func TestReloader(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		certFile = filepath.Join(dir, "tls.crt")
+		keyFile  = filepath.Join(dir, "tls.key")
+		now      = time.Now()
+	)
+
+	writeKeyPair(t, certFile, keyFile, "first", now.Add(-time.Minute))
+
+	r, err := NewReloader(certFile, keyFile, logrus.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reloaded, err := r.Reload(); reloaded || err != nil {
+		t.Errorf("unchanged files reloaded: %v, %v", reloaded, err)
+	}
+
+	writeKeyPair(t, certFile, keyFile, "second", now)
+
+	if reloaded, err := r.Reload(); !reloaded || err != nil {
+		t.Fatalf("changed files not reloaded: %v, %v", reloaded, err)
+	}
+
+	if name := commonName(t, r); name != "second" {
+		t.Errorf("serving %q", name)
+	}
+
+	// A half written pair is refused and the last good one kept.
+	if err := os.WriteFile(keyFile, []byte("garbage"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	later := now.Add(time.Minute)
+	if err := os.Chtimes(keyFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Reload(); err == nil {
+		t.Error("invalid key pair loaded")
+	}
+
+	if name := commonName(t, r); name != "second" {
+		t.Errorf("serving %q after a failed reload", name)
+	}
+}
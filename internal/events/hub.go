@@ -0,0 +1,141 @@
+// Package events is an in-process pub/sub hub that services publish live
+// events into and the event stream endpoints read from.
+package events
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	m "github.com/cs2-server/backend/internal/model"
+)
+
+type Subscription struct {
+	C <-chan m.Event
+
+	ch     chan m.Event
+	mu     sync.RWMutex
+	topics map[m.Topic]bool
+}
+
+// SetTopics replaces the topics the subscription receives. No topics means
+// every topic.
+func (s *Subscription) SetTopics(topics []m.Topic) {
+	set := make(map[m.Topic]bool, len(topics))
+	for _, t := range topics {
+		set[t] = true
+	}
+
+	s.mu.Lock()
+	s.topics = set
+	s.mu.Unlock()
+}
+
+func (s *Subscription) wants(topic m.Topic) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.topics) == 0 || s.topics[topic]
+}
+
+type Hub struct {
+	buffer int
+
+	mu     sync.RWMutex
+	subs   map[*Subscription]struct{}
+	closed bool
+
+	dropped atomic.Uint64
+}
+
+// NewHub creates a hub whose subscribers can lag behind by buffer events
+// before events to them start being dropped.
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		buffer: buffer,
+		subs:   make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish delivers the event to every subscriber of its topic without
+// blocking. Subscribers whose buffer is full miss the event.
+func (h *Hub) Publish(ev m.Event) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return
+	}
+
+	for sub := range h.subs {
+		if !sub.wants(ev.Topic) {
+			continue
+		}
+
+		select {
+		case sub.ch <- ev:
+		default:
+			h.dropped.Add(1)
+		}
+	}
+}
+
+// Subscribe registers a subscriber. Its channel is closed by Unsubscribe or
+// when the hub is closed; a subscription to a closed hub is closed already.
+func (h *Hub) Subscribe(topics []m.Topic) *Subscription {
+	ch := make(chan m.Event, h.buffer)
+	sub := &Subscription{C: ch, ch: ch}
+	sub.SetTopics(topics)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		close(ch)
+
+		return sub
+	}
+
+	h.subs[sub] = struct{}{}
+
+	return sub
+}
+
+func (h *Hub) Unsubscribe(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subs[sub]; !ok {
+		return
+	}
+
+	delete(h.subs, sub)
+	close(sub.ch)
+}
+
+// Close ends every subscription so that stream handlers return. It is meant
+// to run from http.Server.RegisterOnShutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return
+	}
+
+	h.closed = true
+
+	for sub := range h.subs {
+		delete(h.subs, sub)
+		close(sub.ch)
+	}
+}
+
+// Dropped returns how many events were not delivered to slow subscribers.
+func (h *Hub) Dropped() uint64 {
+	return h.dropped.Load()
+}
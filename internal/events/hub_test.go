@@ -0,0 +1,72 @@
+package events
+
+import (
+	"testing"
+
+	m "github.com/cs2-server/backend/internal/model"
+)
+
+func TestHubTopics(t *testing.T) {
+	h := NewHub(4)
+
+	all := h.Subscribe(nil)
+	bans := h.Subscribe([]m.Topic{m.TopicBans})
+
+	h.Publish(m.Event{Topic: m.TopicKills, Type: m.EventKill})
+	h.Publish(m.Event{Topic: m.TopicBans, Type: m.EventBanCreated})
+
+	if got := len(all.C); got != 2 {
+		t.Errorf("subscriber to all topics got %d events, want 2", got)
+	}
+
+	if got := len(bans.C); got != 1 {
+		t.Fatalf("subscriber to bans got %d events, want 1", got)
+	}
+
+	if ev := <-bans.C; ev.Type != m.EventBanCreated || ev.Time.IsZero() {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+
+	bans.SetTopics([]m.Topic{m.TopicKills})
+	h.Publish(m.Event{Topic: m.TopicBans, Type: m.EventBanLifted})
+
+	if got := len(bans.C); got != 0 {
+		t.Errorf("resubscribed subscriber got %d events, want 0", got)
+	}
+}
+
+func TestHubDropsForSlowSubscribers(t *testing.T) {
+	h := NewHub(1)
+	sub := h.Subscribe(nil)
+
+	h.Publish(m.Event{Topic: m.TopicKills})
+	h.Publish(m.Event{Topic: m.TopicKills})
+
+	if got := len(sub.C); got != 1 {
+		t.Errorf("got %d buffered events, want 1", got)
+	}
+
+	if got := h.Dropped(); got != 1 {
+		t.Errorf("got %d dropped events, want 1", got)
+	}
+}
+
+func TestHubClose(t *testing.T) {
+	h := NewHub(1)
+	sub := h.Subscribe(nil)
+
+	h.Close()
+
+	if _, ok := <-sub.C; ok {
+		t.Error("subscription is open after Close")
+	}
+
+	late := h.Subscribe(nil)
+	if _, ok := <-late.C; ok {
+		t.Error("subscription to closed hub is open")
+	}
+
+	// Publishing to and unsubscribing from a closed hub must not panic.
+	h.Publish(m.Event{Topic: m.TopicKills})
+	h.Unsubscribe(sub)
+}
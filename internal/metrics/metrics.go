@@ -0,0 +1,108 @@
+// Package metrics collects the Prometheus metrics exposed at /metrics.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "backend"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	steamCalls   *prometheus.HistogramVec
+	steamErrors  *prometheus.CounterVec
+	jwtFailures  *prometheus.CounterVec
+	panics       prometheus.Counter
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route pattern.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		steamCalls: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "steam_api_duration_seconds",
+			Help:      "Steam Web API call latency by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		steamErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "steam_api_errors_total",
+			Help:      "Failed Steam Web API calls by method.",
+		}, []string{"method"}),
+		jwtFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "jwt_verification_failures_total",
+			Help:      "Rejected JWTs by reason.",
+		}, []string{"reason"}),
+		panics: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_panics_total",
+			Help:      "Handler panics recovered.",
+		}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.steamCalls,
+		m.steamErrors,
+		m.jwtFailures,
+		m.panics,
+	)
+
+	return m
+}
+
+// RegisterPool exports the stats of the connection pool.
+func (m *Metrics) RegisterPool(db *pgxpool.Pool) {
+	m.registry.MustRegister(newPoolCollector(db))
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveHTTP(method, route string, status int, d time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(d.Seconds())
+}
+
+func (m *Metrics) ObserveSteam(method string, d time.Duration, err error) {
+	m.steamCalls.WithLabelValues(method).Observe(d.Seconds())
+
+	if err != nil {
+		m.steamErrors.WithLabelValues(method).Inc()
+	}
+}
+
+func (m *Metrics) JWTFailure(reason string) {
+	m.jwtFailures.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) Panic() {
+	m.panics.Inc()
+}
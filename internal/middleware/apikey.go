@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+
+	ErrInvalidAPIKey = "invalid api key"
+	ErrMissingScope  = "api key lacks required scope"
+	ErrClientCert    = "client certificate required"
+)
+
+type apiKeyCtxKey struct{}
+
+type apiKeyAuthenticator interface {
+	Authenticate(context.Context, string, string) (m.APIKey, error)
+}
+
+type APIKeys struct {
+	authenticator     apiKeyAuthenticator
+	requireClientCert bool
+	logger            *logrus.Logger
+}
+
+func NewAPIKeys(authenticator apiKeyAuthenticator, logger *logrus.Logger) *APIKeys {
+	return &APIKeys{
+		authenticator: authenticator,
+		logger:        logger,
+	}
+}
+
+// RequireClientCert makes keys valid only on connections with a verified
+// TLS client certificate.
+func (k *APIKeys) RequireClientCert() {
+	k.requireClientCert = true
+}
+
+// Auth lets the request through only if it carries a valid game server key
+// with scope in the X-API-Key header.
+func (k *APIKeys) Auth(scope m.APIKeyScope) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return k.auth(scope, next)
+	}
+}
+
+func (k *APIKeys) auth(scope m.APIKeyScope, next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		plain := r.Header.Get(APIKeyHeader)
+		if plain == "" {
+			k.logger.WithContext(r.Context()).Errorln("APIKey (1): api key is missing")
+			render.Error(w, http.StatusUnauthorized, ErrInvalidAPIKey)
+
+			return
+		}
+
+		if k.requireClientCert && (r.TLS == nil || len(r.TLS.VerifiedChains) == 0) {
+			k.logger.WithContext(r.Context()).Errorln("APIKey (2): client certificate is missing")
+			render.Error(w, http.StatusUnauthorized, ErrClientCert)
+
+			return
+		}
+
+		key, err := k.authenticator.Authenticate(r.Context(), plain, ClientIP(r))
+		if err != nil {
+			k.logger.WithContext(r.Context()).Errorln("APIKey (3):", err)
+
+			if errors.Is(err, m.ErrNotFound) {
+				render.Error(w, http.StatusUnauthorized, ErrInvalidAPIKey)
+
+				return
+			}
+
+			render.Error(w, http.StatusInternalServerError, err.Error())
+
+			return
+		}
+
+		if !key.Has(scope) {
+			k.logger.WithContext(r.Context()).Errorf("APIKey (4): key %d lacks %s", key.ID, scope)
+			render.Error(w, http.StatusForbidden, ErrMissingScope)
+
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyCtxKey{}, key)))
+	})
+}
+
+// AuthOr sends requests carrying an API key through Auth and all others
+// through fallback, so game servers and players can share a route. A typical
+// fallback is jwt.Auth followed by Permissions.Require.
+func (k *APIKeys) AuthOr(scope m.APIKeyScope, fallback func(http.HandlerFunc) http.HandlerFunc) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		withKey := k.auth(scope, next)
+		withFallback := fallback(next)
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get(APIKeyHeader) != "" {
+				withKey(w, r)
+
+				return
+			}
+
+			withFallback(w, r)
+		})
+	}
+}
+
+// APIKey returns the game server key authenticated by APIKeys.Auth.
+func APIKey(ctx context.Context) (m.APIKey, bool) {
+	key, ok := ctx.Value(apiKeyCtxKey{}).(m.APIKey)
+
+	return key, ok
+}
@@ -0,0 +1,106 @@
+package middleware
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/sirupsen/logrus"
+)
+
+type fakeAuthenticator map[string]m.APIKey
+
+func (f fakeAuthenticator) Authenticate(_ context.Context, plain, _ string) (m.APIKey, error) {
+	key, ok := f[plain]
+	if !ok {
+		return m.APIKey{}, m.ErrNotFound
+	}
+
+	return key, nil
+}
+
+func TestAPIKeysAuthOr(t *testing.T) {
+	keys := NewAPIKeys(fakeAuthenticator{
+		"cs2_write": {ID: 1, ServerID: 7, Scopes: []m.APIKeyScope{m.ScopeBansWrite}},
+		"cs2_check": {ID: 2, ServerID: 7, Scopes: []m.APIKeyScope{m.ScopeBansCheck}},
+	}, logrus.New())
+
+	fallback := func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}
+	}
+
+	handler := keys.AuthOr(m.ScopeBansWrite, fallback)(func(w http.ResponseWriter, r *http.Request) {
+		if key, ok := APIKey(r.Context()); !ok || key.Actor() != "server:7" {
+			t.Errorf("unexpected key in context: %+v", key)
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name string
+		key  string
+		want int
+	}{
+		{"no key falls back", "", http.StatusTeapot},
+		{"key with scope", "cs2_write", http.StatusOK},
+		{"key without scope", "cs2_check", http.StatusForbidden},
+		{"unknown key", "cs2_unknown", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/bans", nil)
+			if tt.key != "" {
+				r.Header.Set(APIKeyHeader, tt.key)
+			}
+
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("got %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIKeysRequireClientCert(t *testing.T) {
+	keys := NewAPIKeys(fakeAuthenticator{
+		"cs2_check": {ID: 2, ServerID: 7, Scopes: []m.APIKeyScope{m.ScopeBansCheck}},
+	}, logrus.New())
+	keys.RequireClientCert()
+
+	handler := keys.Auth(m.ScopeBansCheck)(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name  string
+		state *tls.ConnectionState
+		want  int
+	}{
+		{"plain http", nil, http.StatusUnauthorized},
+		{"no client certificate", &tls.ConnectionState{}, http.StatusUnauthorized},
+		{"verified client certificate", &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/bans/check", nil)
+			r.Header.Set(APIKeyHeader, "cs2_check")
+			r.TLS = tt.state
+
+			w := httptest.NewRecorder()
+			handler(w, r)
+
+			if w.Code != tt.want {
+				t.Errorf("got %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+}
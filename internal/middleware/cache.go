@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// Cache-Control policies for the routes wrapped with Cache.
+const (
+	CacheLive    = "public, max-age=5"
+	CachePublic  = "public, max-age=60"
+	CachePrivate = "private, no-cache"
+)
+
+// Cache sets the Cache-Control policy of a GET route and gives successful
+// responses a strong ETag computed from the body, answering If-None-Match
+// with 304 when the client already has it.
+func Cache(policy string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			bw := &bufferWriter{header: make(http.Header)}
+			next.ServeHTTP(bw, r)
+
+			h := w.Header()
+			for k, v := range bw.header {
+				h[k] = v
+			}
+
+			if bw.status == 0 {
+				bw.status = http.StatusOK
+			}
+
+			if bw.status != http.StatusOK {
+				w.WriteHeader(bw.status)
+				w.Write(bw.body.Bytes())
+
+				return
+			}
+
+			sum := sha256.Sum256(bw.body.Bytes())
+			etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+			h.Set("ETag", etag)
+			h.Set("Cache-Control", policy)
+
+			if etagMatch(r.Header.Get("If-None-Match"), etag) {
+				h.Del("Content-Type")
+				h.Del("Content-Length")
+				w.WriteHeader(http.StatusNotModified)
+
+				return
+			}
+
+			w.WriteHeader(http.StatusOK)
+			w.Write(bw.body.Bytes())
+		})
+	}
+}
+
+// etagMatch compares weakly, as If-None-Match requires, and ignores the
+// encoding suffix Compress adds so a compressed copy validates too.
+func etagMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+
+		for _, encoding := range []string{encodingBrotli, encodingGzip} {
+			if candidate == strings.TrimSuffix(etag, `"`)+"-"+encoding+`"` {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// bufferWriter holds the whole response, which Cache needs to hash before
+// anything is sent.
+type bufferWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *bufferWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	return w.body.Write(b)
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	body := `{"map":"de_inferno"}`
+
+	handler := Cache(CachePublic)(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/api/servers/1", nil))
+
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || w.Body.String() != body || etag == "" {
+		t.Fatalf("first response: %d %q etag %q", w.Code, w.Body.String(), etag)
+	}
+
+	if got := w.Header().Get("Cache-Control"); got != CachePublic {
+		t.Errorf("cache control %q, want %q", got, CachePublic)
+	}
+
+	for _, inm := range []string{etag, "W/" + etag, `"other", ` + etag, etag[:len(etag)-1] + `-gzip"`} {
+		r := httptest.NewRequest(http.MethodGet, "/api/servers/1", nil)
+		r.Header.Set("If-None-Match", inm)
+
+		w := httptest.NewRecorder()
+		handler(w, r)
+
+		if w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: %d %q", inm, w.Code, w.Body.String())
+		}
+
+		if w.Header().Get("ETag") != etag {
+			t.Errorf("If-None-Match %s: etag %q", inm, w.Header().Get("ETag"))
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/api/servers/1", nil)
+	r.Header.Set("If-None-Match", `"stale"`)
+
+	w = httptest.NewRecorder()
+	handler(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("stale etag: status %d", w.Code)
+	}
+}
+
+func TestCacheSkipsErrors(t *testing.T) {
+	handler := Cache(CachePublic)(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/api/servers/1", nil))
+
+	if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" || w.Header().Get("Cache-Control") != "" {
+		t.Errorf("error response cached: %d %v", w.Code, w.Header())
+	}
+}
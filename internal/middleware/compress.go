@@ -0,0 +1,238 @@
+package middleware
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	encodingBrotli = "br"
+	encodingGzip   = "gzip"
+
+	// compressMinSize is the body size below which compressing is not worth
+	// the CPU and the bytes of the encoding overhead.
+	compressMinSize = 512
+
+	brotliLevel = 4
+)
+
+// Compress encodes responses with brotli or gzip, whichever the client
+// prefers, br on a tie. Small bodies, event streams and WebSocket upgrades are
+// sent as they are.
+func Compress(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		encoding := negotiateEncoding(r.Header.Get("Accept-Encoding"))
+		if encoding == "" || r.Method == http.MethodHead || r.Header.Get("Upgrade") != "" {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		cw := &compressWriter{ResponseWriter: w, encoding: encoding}
+		defer cw.Close()
+
+		next.ServeHTTP(cw, r)
+	})
+}
+
+// negotiateEncoding picks br or gzip from an Accept-Encoding header, honoring
+// q-values. It returns an empty string when neither is acceptable.
+func negotiateEncoding(header string) string {
+	var brQ, gzipQ, anyQ float64 = -1, -1, -1
+
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+
+			q = parsed
+		}
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case encodingBrotli:
+			brQ = q
+		case encodingGzip:
+			gzipQ = q
+		case "*":
+			anyQ = q
+		}
+	}
+
+	if brQ < 0 {
+		brQ = anyQ
+	}
+
+	if gzipQ < 0 {
+		gzipQ = anyQ
+	}
+
+	switch {
+	case brQ > 0 && brQ >= gzipQ:
+		return encodingBrotli
+	case gzipQ > 0:
+		return encodingGzip
+	default:
+		return ""
+	}
+}
+
+// compressWriter holds back the first compressMinSize bytes to decide whether
+// to compress, so headers are only sent once the decision is made.
+type compressWriter struct {
+	http.ResponseWriter
+	encoding string
+
+	status  int
+	buf     []byte
+	started bool
+	enc     io.WriteCloser
+}
+
+func (w *compressWriter) WriteHeader(status int) {
+	if w.started || w.status != 0 {
+		return
+	}
+
+	w.status = status
+
+	// Informational, 204 and 304 responses have no body to wait for.
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		w.start(false)
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if !w.started {
+		if !w.compressible() {
+			w.start(false)
+		} else {
+			w.buf = append(w.buf, b...)
+			if len(w.buf) < compressMinSize {
+				return len(b), nil
+			}
+
+			buffered := w.buf
+			w.buf = nil
+			w.start(true)
+
+			if _, err := w.enc.Write(buffered); err != nil {
+				return 0, err
+			}
+
+			return len(b), nil
+		}
+	}
+
+	if w.enc != nil {
+		return w.enc.Write(b)
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends whatever was held back uncompressed if compression has not
+// started, as the client evidently wants the data now.
+func (w *compressWriter) Flush() {
+	if !w.started {
+		w.start(false)
+	}
+
+	if f, ok := w.enc.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *compressWriter) Close() error {
+	if !w.started {
+		if w.status == 0 {
+			return nil
+		}
+
+		w.start(false)
+	}
+
+	if w.enc != nil {
+		return w.enc.Close()
+	}
+
+	return nil
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack is not supported")
+	}
+
+	return h.Hijack()
+}
+
+func (w *compressWriter) compressible() bool {
+	h := w.Header()
+
+	return h.Get("Content-Encoding") == "" &&
+		!strings.HasPrefix(h.Get("Content-Type"), "text/event-stream")
+}
+
+// start sends the headers and whatever was buffered, with or without
+// compression.
+func (w *compressWriter) start(compress bool) {
+	w.started = true
+
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	if compress {
+		h := w.Header()
+		h.Set("Content-Encoding", w.encoding)
+		h.Del("Content-Length")
+
+		// The compressed body is a different representation, so it needs a
+		// different strong validator.
+		if etag := h.Get("ETag"); strings.HasPrefix(etag, `"`) {
+			h.Set("ETag", strings.TrimSuffix(etag, `"`)+"-"+w.encoding+`"`)
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if compress {
+		switch w.encoding {
+		case encodingBrotli:
+			w.enc = brotli.NewWriterLevel(w.ResponseWriter, brotliLevel)
+		default:
+			w.enc = gzip.NewWriter(w.ResponseWriter)
+		}
+	}
+
+	if len(w.buf) > 0 {
+		w.ResponseWriter.Write(w.buf)
+		w.buf = nil
+	}
+}
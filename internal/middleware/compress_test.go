@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestNegotiateEncoding(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{"gzip", encodingGzip},
+		{"gzip, deflate, br", encodingBrotli},
+		{"br;q=0.5, gzip;q=0.8", encodingGzip},
+		{"br;q=0, gzip", encodingGzip},
+		{"*", encodingBrotli},
+		{"*;q=0.5, br;q=0", encodingGzip},
+		{"identity", ""},
+	}
+
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestCompress(t *testing.T) {
+	large := strings.Repeat(`{"name":"de_dust2"}`, 100)
+
+	tests := []struct {
+		name     string
+		body     string
+		accept   string
+		encoding string
+	}{
+		{"gzip", large, "gzip", encodingGzip},
+		{"brotli", large, "gzip, br", encodingBrotli},
+		{"small body", `{"ok":true}`, "gzip", ""},
+		{"not accepted", large, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Compress(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Header().Set("ETag", `"abc"`)
+				io.WriteString(w, tt.body)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/api/servers", nil)
+			r.Header.Set("Accept-Encoding", tt.accept)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if got := w.Header().Get("Content-Encoding"); got != tt.encoding {
+				t.Fatalf("content encoding %q, want %q", got, tt.encoding)
+			}
+
+			var body io.Reader = w.Body
+			wantETag := `"abc"`
+
+			switch tt.encoding {
+			case encodingGzip:
+				zr, err := gzip.NewReader(w.Body)
+				if err != nil {
+					t.Fatalf("gzip: %v", err)
+				}
+
+				body = zr
+				wantETag = `"abc-gzip"`
+			case encodingBrotli:
+				body = brotli.NewReader(w.Body)
+				wantETag = `"abc-br"`
+			}
+
+			got, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("read body: %v", err)
+			}
+
+			if string(got) != tt.body {
+				t.Errorf("body %q, want %q", got, tt.body)
+			}
+
+			if etag := w.Header().Get("ETag"); etag != wantETag {
+				t.Errorf("etag %s, want %s", etag, wantETag)
+			}
+		})
+	}
+}
@@ -1,17 +1,236 @@
 package middleware
 
-import "net/http"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
 
-func CORS(next http.Handler) http.Handler {
+	"github.com/cs2-server/backend/config"
+)
+
+// wildcardOrigin matches the subdomains of suffix under scheme, for
+// https://*.example.com the prefix is "https://" and the suffix ".example.com".
+type wildcardOrigin struct {
+	prefix string
+	suffix string
+}
+
+func (o wildcardOrigin) match(origin string) bool {
+	return len(origin) > len(o.prefix)+len(o.suffix) &&
+		strings.HasPrefix(origin, o.prefix) &&
+		strings.HasSuffix(origin, o.suffix)
+}
+
+// CORSPolicy holds the CORS rules, which can be swapped with Reload while
+// requests are served.
+type CORSPolicy struct {
+	rules atomic.Pointer[corsRules]
+}
+
+type corsRules struct {
+	anyOrigin   bool
+	origins     map[string]struct{}
+	wildcards   []wildcardOrigin
+	methods     map[string]struct{}
+	headers     map[string]struct{}
+	credentials bool
+
+	allowMethods  string
+	allowHeaders  string
+	exposeHeaders string
+	maxAge        string
+}
+
+func NewCORSPolicy(cfg config.CORS) (*CORSPolicy, error) {
+	var p CORSPolicy
+
+	if err := p.Reload(cfg); err != nil {
+		return nil, fmt.Errorf("NewCORSPolicy: %w", err)
+	}
+
+	return &p, nil
+}
+
+// Reload replaces the rules with those of cfg, unless cfg is invalid.
+func (p *CORSPolicy) Reload(cfg config.CORS) error {
+	rules, err := newCORSRules(cfg)
+	if err != nil {
+		return fmt.Errorf("Reload: %w", err)
+	}
+
+	p.rules.Store(rules)
+
+	return nil
+}
+
+func newCORSRules(cfg config.CORS) (*corsRules, error) {
+	p := &corsRules{
+		origins:       make(map[string]struct{}),
+		methods:       make(map[string]struct{}),
+		headers:       make(map[string]struct{}),
+		credentials:   cfg.AllowCredentials,
+		allowMethods:  strings.Join(cfg.AllowedMethods, ", "),
+		allowHeaders:  strings.Join(cfg.AllowedHeaders, ", "),
+		exposeHeaders: strings.Join(cfg.ExposedHeaders, ", "),
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSpace(origin))
+
+		switch {
+		case origin == "":
+		case origin == "*":
+			p.anyOrigin = true
+		case strings.Contains(origin, "*"):
+			scheme, host, ok := strings.Cut(origin, "://*.")
+			if !ok || scheme == "" || host == "" || strings.Contains(host, "*") {
+				return nil, fmt.Errorf("newCORSRules (1): invalid origin pattern %q", origin)
+			}
+
+			p.wildcards = append(p.wildcards, wildcardOrigin{prefix: scheme + "://", suffix: "." + host})
+		default:
+			p.origins[strings.TrimSuffix(origin, "/")] = struct{}{}
+		}
+	}
+
+	if p.anyOrigin && p.credentials {
+		return nil, errors.New("newCORSRules (2): credentials cannot be allowed for every origin")
+	}
+
+	for _, method := range cfg.AllowedMethods {
+		p.methods[strings.ToUpper(strings.TrimSpace(method))] = struct{}{}
+	}
+
+	for _, header := range cfg.AllowedHeaders {
+		p.headers[http.CanonicalHeaderKey(strings.TrimSpace(header))] = struct{}{}
+	}
+
+	if cfg.MaxAge > 0 {
+		p.maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	}
+
+	return p, nil
+}
+
+// AllowOrigin reports whether requests from origin are allowed.
+func (p *CORSPolicy) AllowOrigin(origin string) bool {
+	return p.rules.Load().allowOrigin(origin)
+}
+
+func (p *corsRules) allowOrigin(origin string) bool {
+	if p.anyOrigin {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+
+	if _, ok := p.origins[origin]; ok {
+		return true
+	}
+
+	for _, w := range p.wildcards {
+		if w.match(origin) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Handler answers preflight requests and adds CORS headers to the responses
+// of next for allowed origins. Responses to other origins carry no CORS
+// headers, so browsers refuse to hand them to the page.
+func (p *CORSPolicy) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		rules := p.rules.Load()
+
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+
+			return
+		}
+
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+		if !rules.anyOrigin {
+			w.Header().Add("Vary", "Origin")
+		}
+
+		if preflight {
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+			rules.preflight(w, r, origin)
 
-		if r.Method == http.MethodOptions {
 			return
 		}
 
+		if rules.allowOrigin(origin) {
+			rules.setOrigin(w, origin)
+
+			if rules.exposeHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", rules.exposeHeaders)
+			}
+		}
+
 		next.ServeHTTP(w, r)
 	})
 }
+
+func (p *corsRules) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	if !p.allowOrigin(origin) {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	if _, ok := p.methods[strings.ToUpper(r.Header.Get("Access-Control-Request-Method"))]; !ok {
+		w.WriteHeader(http.StatusForbidden)
+
+		return
+	}
+
+	for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+
+		if _, ok := p.headers[http.CanonicalHeaderKey(header)]; !ok {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+	}
+
+	p.setOrigin(w, origin)
+	w.Header().Set("Access-Control-Allow-Methods", p.allowMethods)
+
+	if p.allowHeaders != "" {
+		w.Header().Set("Access-Control-Allow-Headers", p.allowHeaders)
+	}
+
+	if p.maxAge != "" {
+		w.Header().Set("Access-Control-Max-Age", p.maxAge)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (p *corsRules) setOrigin(w http.ResponseWriter, origin string) {
+	if p.anyOrigin {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+
+	if p.credentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cs2-server/backend/config"
+)
+
+func TestCORSPolicy(t *testing.T) {
+	policy, err := NewCORSPolicy(config.CORS{
+		AllowedOrigins:   []string{"https://app.example.com", "https://*.example.org"},
+		AllowedMethods:   []string{"GET", "POST"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		ExposedHeaders:   []string{"Retry-After"},
+		AllowCredentials: true,
+		MaxAge:           time.Minute,
+	})
+	if err != nil {
+		t.Fatalf("NewCORSPolicy: %v", err)
+	}
+
+	handler := policy.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name        string
+		method      string
+		origin      string
+		reqMethod   string
+		reqHeaders  string
+		wantStatus  int
+		wantOrigin  string
+		wantMaxAge  string
+		wantExposed string
+	}{
+		{name: "no origin", method: http.MethodGet, wantStatus: http.StatusOK},
+		{
+			name: "exact origin", method: http.MethodGet, origin: "https://app.example.com",
+			wantStatus: http.StatusOK, wantOrigin: "https://app.example.com", wantExposed: "Retry-After",
+		},
+		{
+			name: "wildcard subdomain", method: http.MethodGet, origin: "https://eu.example.org",
+			wantStatus: http.StatusOK, wantOrigin: "https://eu.example.org", wantExposed: "Retry-After",
+		},
+		{name: "wildcard apex", method: http.MethodGet, origin: "https://example.org", wantStatus: http.StatusOK},
+		{name: "wildcard other scheme", method: http.MethodGet, origin: "http://eu.example.org", wantStatus: http.StatusOK},
+		{name: "unknown origin", method: http.MethodGet, origin: "https://evil.com", wantStatus: http.StatusOK},
+		{
+			name: "preflight", method: http.MethodOptions, origin: "https://app.example.com",
+			reqMethod: "POST", reqHeaders: "content-type, authorization",
+			wantStatus: http.StatusNoContent, wantOrigin: "https://app.example.com", wantMaxAge: "60",
+		},
+		{
+			name: "preflight method not allowed", method: http.MethodOptions, origin: "https://app.example.com",
+			reqMethod: "DELETE", wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "preflight header not allowed", method: http.MethodOptions, origin: "https://app.example.com",
+			reqMethod: "POST", reqHeaders: "X-Custom", wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "preflight unknown origin", method: http.MethodOptions, origin: "https://evil.com",
+			reqMethod: "GET", wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "options without preflight", method: http.MethodOptions, origin: "https://app.example.com",
+			wantStatus: http.StatusOK, wantOrigin: "https://app.example.com", wantExposed: "Retry-After",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(tt.method, "/api/servers", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+
+			if tt.reqMethod != "" {
+				r.Header.Set("Access-Control-Request-Method", tt.reqMethod)
+			}
+
+			if tt.reqHeaders != "" {
+				r.Header.Set("Access-Control-Request-Headers", tt.reqHeaders)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			h := w.Header()
+
+			if got := h.Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("allow origin %q, want %q", got, tt.wantOrigin)
+			}
+
+			if got := h.Get("Access-Control-Max-Age"); got != tt.wantMaxAge {
+				t.Errorf("max age %q, want %q", got, tt.wantMaxAge)
+			}
+
+			if got := h.Get("Access-Control-Expose-Headers"); got != tt.wantExposed {
+				t.Errorf("exposed headers %q, want %q", got, tt.wantExposed)
+			}
+
+			if tt.wantOrigin != "" && h.Get("Access-Control-Allow-Credentials") != "true" {
+				t.Error("credentials not allowed")
+			}
+
+			if tt.origin != "" && h.Get("Vary") != "Origin" {
+				t.Errorf("vary %q, want Origin", h.Values("Vary"))
+			}
+		})
+	}
+}
+
+func TestCORSPolicyInvalid(t *testing.T) {
+	for _, cfg := range []config.CORS{
+		{AllowedOrigins: []string{"*"}, AllowCredentials: true},
+		{AllowedOrigins: []string{"https://*"}},
+		{AllowedOrigins: []string{"https://a.*.example.com"}},
+	} {
+		if _, err := NewCORSPolicy(cfg); err == nil {
+			t.Errorf("NewCORSPolicy(%+v): expected error", cfg)
+		}
+	}
+}
+
+func TestCORSPolicyReload(t *testing.T) {
+	policy, err := NewCORSPolicy(config.CORS{AllowedOrigins: []string{"https://old.example.com"}})
+	if err != nil {
+		t.Fatalf("NewCORSPolicy: %v", err)
+	}
+
+	if err := policy.Reload(config.CORS{AllowedOrigins: []string{"https://new.example.com"}}); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	if policy.AllowOrigin("https://old.example.com") || !policy.AllowOrigin("https://new.example.com") {
+		t.Error("rules not replaced")
+	}
+
+	if err := policy.Reload(config.CORS{AllowedOrigins: []string{"*"}, AllowCredentials: true}); err == nil {
+		t.Fatal("invalid rules accepted")
+	}
+
+	if !policy.AllowOrigin("https://new.example.com") {
+		t.Error("invalid reload replaced the rules")
+	}
+}
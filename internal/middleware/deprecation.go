@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Deprecated marks responses of an old alias route: Deprecation (RFC 9745)
+// and Sunset (RFC 8594) carry the dates, and Link points at the same path
+// under successor, which replaces prefix.
+func Deprecated(deprecated, sunset time.Time, prefix, successor string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := w.Header()
+			h.Set("Deprecation", "@"+strconv.FormatInt(deprecated.Unix(), 10))
+			h.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+
+			if rest, ok := strings.CutPrefix(r.URL.Path, prefix); ok {
+				h.Add("Link", "<"+successor+rest+`>; rel="successor-version"`)
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
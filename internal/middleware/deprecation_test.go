@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeprecated(t *testing.T) {
+	var (
+		deprecated = time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		sunset     = time.Date(2027, 5, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	handler := Deprecated(deprecated, sunset, "/api", "/api/v1")(func(w http.ResponseWriter, r *http.Request) {})
+
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodGet, "/api/servers/3?x=1", nil))
+
+	for header, want := range map[string]string{
+		"Deprecation": "@1793491200",
+		"Sunset":      "Sat, 01 May 2027 00:00:00 GMT",
+		"Link":        `</api/v1/servers/3>; rel="successor-version"`,
+	} {
+		if got := w.Header().Get(header); got != want {
+			t.Errorf("%s: %q, want %q", header, got, want)
+		}
+	}
+}
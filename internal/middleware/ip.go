@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+)
+
+type clientCtxKey struct{}
+
+// client is where a request came from as resolved by TrustedProxies.
+type client struct {
+	ip     string
+	scheme string
+	host   string
+}
+
+// ClientIP returns the address the request came from, which is that of the
+// proxy unless it is trusted by TrustedProxies.
+func ClientIP(r *http.Request) string {
+	if c, ok := r.Context().Value(clientCtxKey{}).(client); ok {
+		return c.ip
+	}
+
+	return remoteIP(r)
+}
+
+// Scheme returns the scheme the client used, http or https.
+func Scheme(r *http.Request) string {
+	if c, ok := r.Context().Value(clientCtxKey{}).(client); ok {
+		return c.scheme
+	}
+
+	return localScheme(r)
+}
+
+// Host returns the host the client sent the request to.
+func Host(r *http.Request) string {
+	if c, ok := r.Context().Value(clientCtxKey{}).(client); ok {
+		return c.host
+	}
+
+	return r.Host
+}
+
+// BaseURL returns the scheme and host of the request as the client sees
+// them, such as https://api.example.com, for building absolute URLs.
+func BaseURL(r *http.Request) string {
+	return Scheme(r) + "://" + Host(r)
+}
+
+func withClient(ctx context.Context, c client) context.Context {
+	return context.WithValue(ctx, clientCtxKey{}, c)
+}
+
+func remoteIP(r *http.Request) string {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+
+	return ip
+}
+
+func localScheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+
+	return "http"
+}
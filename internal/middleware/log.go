@@ -1,23 +1,234 @@
 package middleware
 
 import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
 	"net/http"
+	"net/url"
 	"time"
 
+	"github.com/cs2-server/backend/pkg/jwt"
 	"github.com/sirupsen/logrus"
 )
 
-func Log(next http.HandlerFunc) http.HandlerFunc {
+const (
+	RequestIDHeader = "X-Request-ID"
+
+	maxRequestIDLength = 64
+	redacted           = "REDACTED"
+)
+
+// sensitiveParams are query parameters that are never logged. The OpenID ones
+// are enough to replay a Steam login.
+var sensitiveParams = []string{
+	"access_token",
+	"openid.sig",
+	"openid.response_nonce",
+	"openid.assoc_handle",
+}
+
+type requestLogKey struct{}
+
+// requestLog is shared by the middlewares of a single request. Routes fill in
+// the caller once it is authenticated, the access log line reads it at the end.
+type requestLog struct {
+	id     string
+	caller string
+}
+
+type AccessLog struct {
+	logger *logrus.Logger
+}
+
+func NewAccessLog(logger *logrus.Logger) *AccessLog {
+	return &AccessLog{
+		logger: logger,
+	}
+}
+
+// Handler assigns the request an ID, taken from X-Request-ID when the client
+// sent a sane one, echoes it back and logs the request once it is handled.
+func (l *AccessLog) Handler(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
 
-		next.ServeHTTP(w, r)
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+
+		rl := &requestLog{id: id}
+		rw := &responseWriter{ResponseWriter: w}
+
+		next.ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), requestLogKey{}, rl)))
 
-		logrus.WithFields(logrus.Fields{
-			"method":   r.Method,
-			"path":     r.URL.Path,
-			"query":    r.URL.RawQuery,
-			"duration": time.Since(start),
+		l.logger.WithFields(logrus.Fields{
+			"request_id": id,
+			"method":     r.Method,
+			"path":       r.URL.Path,
+			"query":      redactQuery(r.URL.RawQuery),
+			"status":     rw.Status(),
+			"bytes":      rw.bytes,
+			"duration":   time.Since(start),
+			"ip":         ClientIP(r),
+			"user_agent": r.UserAgent(),
+			"caller":     rl.caller,
 		}).Info("request handled")
 	})
 }
+
+// Log records who made the request for the access log line. It goes inside
+// jwt.Auth or APIKeys.Auth so the caller is known.
+func (l *AccessLog) Log(next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rl, ok := r.Context().Value(requestLogKey{}).(*requestLog); ok {
+			if steamID, ok := jwt.SteamID(r.Context()); ok {
+				rl.caller = steamID
+			} else if key, ok := APIKey(r.Context()); ok {
+				rl.caller = key.Actor()
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequestID returns the ID AccessLog assigned to the request.
+func RequestID(ctx context.Context) (string, bool) {
+	rl, ok := ctx.Value(requestLogKey{}).(*requestLog)
+	if !ok {
+		return "", false
+	}
+
+	return rl.id, true
+}
+
+// RequestIDHook adds the request ID to entries logged with the request
+// context, as in logger.WithContext(r.Context()).
+type RequestIDHook struct{}
+
+func (RequestIDHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (RequestIDHook) Fire(e *logrus.Entry) error {
+	if e.Context == nil {
+		return nil
+	}
+
+	if id, ok := RequestID(e.Context); ok {
+		e.Data["request_id"] = id
+	}
+
+	return nil
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
+
+func redactQuery(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	query, err := url.ParseQuery(raw)
+	if err != nil {
+		// Unparsable queries could hide anything.
+		return redacted
+	}
+
+	for _, param := range sensitiveParams {
+		if query.Has(param) {
+			query.Set(param, redacted)
+		}
+	}
+
+	return query.Encode()
+}
+
+// responseWriter remembers the status code and the size of the body.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+
+	return n, err
+}
+
+// Status is 200 if nothing was written, as net/http would send.
+func (w *responseWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+
+	return w.status
+}
+
+// Unwrap lets http.ResponseController reach the flusher and deadlines of the
+// underlying writer.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *responseWriter) Flush() {
+	http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack is used for WebSocket upgrades, which are logged as 101.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("hijack is not supported")
+	}
+
+	conn, rw, err := h.Hijack()
+	if err == nil && w.status == 0 {
+		w.status = http.StatusSwitchingProtocols
+	}
+
+	return conn, rw, err
+}
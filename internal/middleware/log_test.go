@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestAccessLog(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.AddHook(RequestIDHook{})
+
+	accessLog := NewAccessLog(logger)
+
+	handler := accessLog.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logger.WithContext(r.Context()).Errorln("handler failed")
+
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("bad"))
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/auth/process?openid.mode=id_res&openid.sig=s3cr3t&openid.response_nonce=n0nc3", nil)
+	r.Header.Set(RequestIDHeader, "abc-123")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if got := w.Header().Get(RequestIDHeader); got != "abc-123" {
+		t.Errorf("response request id %q, want %q", got, "abc-123")
+	}
+
+	entries := hook.AllEntries()
+	if len(entries) != 2 {
+		t.Fatalf("got %d log entries, want 2", len(entries))
+	}
+
+	if got := entries[0].Data["request_id"]; got != "abc-123" {
+		t.Errorf("handler entry request id %v, want %q", got, "abc-123")
+	}
+
+	access := entries[1]
+
+	for field, want := range map[string]any{
+		"request_id": "abc-123",
+		"status":     http.StatusBadRequest,
+		"bytes":      3,
+		"method":     http.MethodGet,
+	} {
+		if got := access.Data[field]; got != want {
+			t.Errorf("%s = %v, want %v", field, got, want)
+		}
+	}
+
+	query, _ := access.Data["query"].(string)
+	if strings.Contains(query, "s3cr3t") || strings.Contains(query, "n0nc3") {
+		t.Errorf("query not redacted: %s", query)
+	}
+
+	if !strings.Contains(query, "openid.mode=id_res") {
+		t.Errorf("query lost params: %s", query)
+	}
+}
+
+func TestAccessLogGeneratesRequestID(t *testing.T) {
+	logger, _ := test.NewNullLogger()
+
+	var got string
+	handler := NewAccessLog(logger).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got, _ = RequestID(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/api/servers", nil)
+	r.Header.Set(RequestIDHeader, "not valid\n")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if got == "" || got == "not valid\n" {
+		t.Fatalf("request id %q was not generated", got)
+	}
+
+	if w.Header().Get(RequestIDHeader) != got {
+		t.Errorf("response request id %q, want %q", w.Header().Get(RequestIDHeader), got)
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+const unmatchedRoute = "unmatched"
+
+// routeMatcher tells which route pattern a request matches, empty if none.
+type routeMatcher interface {
+	Pattern(*http.Request) string
+}
+
+type httpRecorder interface {
+	ObserveHTTP(method, route string, status int, d time.Duration)
+}
+
+// Metrics records every request under the pattern it matched in routes, such
+// as /api/servers/{id}, so paths with IDs do not each get their own series.
+func Metrics(recorder httpRecorder, routes routeMatcher, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		route := routes.Pattern(r)
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		rw := &responseWriter{ResponseWriter: w}
+
+		next.ServeHTTP(rw, r)
+
+		recorder.ObserveHTTP(metricMethod(r.Method), route, rw.Status(), time.Since(start))
+	})
+}
+
+// metricMethod keeps arbitrary methods from creating new series.
+func metricMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	}
+
+	return "OTHER"
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cs2-server/backend/internal/router"
+)
+
+type observation struct {
+	method, route string
+	status        int
+}
+
+type fakeRecorder []observation
+
+func (f *fakeRecorder) ObserveHTTP(method, route string, status int, _ time.Duration) {
+	*f = append(*f, observation{method, route, status})
+}
+
+func TestMetricsRoutePattern(t *testing.T) {
+	routes := router.New()
+	routes.Group("/api").Get("/servers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	var rec fakeRecorder
+	handler := Metrics(&rec, routes, routes)
+
+	for _, target := range []string{"/api/servers/1", "/api/servers/2", "/unknown"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BREW", "/api/servers/1", nil))
+
+	want := []observation{
+		{http.MethodGet, "/api/servers/{id}", http.StatusNotFound},
+		{http.MethodGet, "/api/servers/{id}", http.StatusNotFound},
+		{http.MethodGet, unmatchedRoute, http.StatusNotFound},
+		{"OTHER", unmatchedRoute, http.StatusMethodNotAllowed},
+	}
+
+	if len(rec) != len(want) {
+		t.Fatalf("got %d observations, want %d", len(rec), len(want))
+	}
+
+	for i := range want {
+		if rec[i] != want[i] {
+			t.Errorf("observation %d: got %+v, want %+v", i, rec[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/cs2-server/backend/pkg/jwt"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ErrForbidden = "forbidden"
+)
+
+type permissionChecker interface {
+	HasPermission(context.Context, string, m.Permission) (bool, error)
+}
+
+type Permissions struct {
+	checker permissionChecker
+	logger  *logrus.Logger
+}
+
+func NewPermissions(checker permissionChecker, logger *logrus.Logger) *Permissions {
+	return &Permissions{
+		checker: checker,
+		logger:  logger,
+	}
+}
+
+// Require lets the request through only if the player authenticated by
+// jwt.Auth has perm, so it must be wrapped by jwt.Auth.
+func (p *Permissions) Require(perm m.Permission) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return p.require(perm, next)
+	}
+}
+
+func (p *Permissions) require(perm m.Permission, next http.HandlerFunc) http.HandlerFunc {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		steamID, ok := jwt.SteamID(r.Context())
+		if !ok {
+			p.logger.WithContext(r.Context()).Errorln("Permissions (1): no authenticated player")
+			render.Error(w, http.StatusUnauthorized, ErrForbidden)
+
+			return
+		}
+
+		allowed, err := p.checker.HasPermission(r.Context(), steamID, perm)
+		if err != nil {
+			p.logger.WithContext(r.Context()).Errorln("Permissions (2):", err)
+			render.Error(w, http.StatusInternalServerError, err.Error())
+
+			return
+		}
+
+		if !allowed {
+			p.logger.WithContext(r.Context()).Errorf("Permissions (3): %s lacks %s", steamID, perm)
+			render.Error(w, http.StatusForbidden, ErrForbidden)
+
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// TrustedProxies reads the client address, scheme and host from the
+// Forwarded or X-Forwarded-* headers of requests relayed by trusted proxies.
+// The headers of anyone else are ignored, as clients can set them freely.
+type TrustedProxies struct {
+	prefixes []netip.Prefix
+}
+
+// NewTrustedProxies accepts CIDRs such as 10.0.0.0/8 and single addresses.
+func NewTrustedProxies(cidrs []string) (*TrustedProxies, error) {
+	p := &TrustedProxies{}
+
+	for _, cidr := range cidrs {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			addr, addrErr := netip.ParseAddr(cidr)
+			if addrErr != nil {
+				return nil, fmt.Errorf("NewTrustedProxies: %w", err)
+			}
+
+			prefix = netip.PrefixFrom(addr, addr.BitLen())
+		}
+
+		p.prefixes = append(p.prefixes, prefix.Masked())
+	}
+
+	return p, nil
+}
+
+// Handler resolves the client of every request for ClientIP, Scheme, Host
+// and BaseURL.
+func (p *TrustedProxies) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(withClient(r.Context(), p.resolve(r))))
+	})
+}
+
+// hop is what one proxy recorded about the request it received.
+type hop struct {
+	ip     string
+	scheme string
+	host   string
+}
+
+func (p *TrustedProxies) resolve(r *http.Request) client {
+	c := client{ip: remoteIP(r), scheme: localScheme(r), host: r.Host}
+
+	if !p.trusted(c.ip) {
+		return c
+	}
+
+	hops := forwardedHops(r.Header)
+	if hops == nil {
+		hops = xForwardedHops(r.Header)
+	}
+
+	// Walk back from the nearest proxy: the first address not trusted is
+	// the client, as anything before it may have been made up.
+	for i := len(hops) - 1; i >= 0; i-- {
+		h := hops[i]
+		if h.ip == "" {
+			break
+		}
+
+		c.ip = h.ip
+
+		if h.scheme == "http" || h.scheme == "https" {
+			c.scheme = h.scheme
+		}
+
+		if h.host != "" {
+			c.host = h.host
+		}
+
+		if !p.trusted(h.ip) {
+			break
+		}
+	}
+
+	return c
+}
+
+func (p *TrustedProxies) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+
+	addr = addr.Unmap()
+
+	for _, prefix := range p.prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// forwardedHops parses the RFC 7239 Forwarded header, such as
+// for=192.0.2.60;proto=https;host=example.com, for="[2001:db8::1]:4711".
+func forwardedHops(header http.Header) []hop {
+	var hops []hop
+
+	for _, line := range header.Values("Forwarded") {
+		for _, element := range strings.Split(line, ",") {
+			var h hop
+
+			for _, pair := range strings.Split(element, ";") {
+				name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+				if !ok {
+					continue
+				}
+
+				value = strings.Trim(value, `"`)
+
+				switch strings.ToLower(name) {
+				case "for":
+					h.ip = nodeIP(value)
+				case "proto":
+					h.scheme = strings.ToLower(value)
+				case "host":
+					h.host = value
+				}
+			}
+
+			hops = append(hops, h)
+		}
+	}
+
+	return hops
+}
+
+// xForwardedHops reads X-Forwarded-For. X-Forwarded-Proto and
+// X-Forwarded-Host are set, not appended, by the nearest proxy, so they go
+// with its hop.
+func xForwardedHops(header http.Header) []hop {
+	var hops []hop
+
+	for _, line := range header.Values("X-Forwarded-For") {
+		for _, ip := range strings.Split(line, ",") {
+			hops = append(hops, hop{ip: nodeIP(strings.TrimSpace(ip))})
+		}
+	}
+
+	if len(hops) == 0 {
+		return nil
+	}
+
+	first := func(name string) string {
+		v, _, _ := strings.Cut(header.Get(name), ",")
+
+		return strings.TrimSpace(v)
+	}
+
+	last := &hops[len(hops)-1]
+	last.scheme = strings.ToLower(first("X-Forwarded-Proto"))
+	last.host = first("X-Forwarded-Host")
+
+	return hops
+}
+
+// nodeIP strips the port and brackets of a node such as [2001:db8::1]:4711
+// and returns an empty string for obfuscated or unknown nodes.
+func nodeIP(node string) string {
+	if host, _, err := net.SplitHostPort(node); err == nil {
+		node = host
+	}
+
+	node = strings.Trim(node, "[]")
+
+	addr, err := netip.ParseAddr(node)
+	if err != nil {
+		return ""
+	}
+
+	return addr.Unmap().String()
+}
@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTrustedProxies(t *testing.T) {
+	proxies, err := NewTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1"})
+	if err != nil {
+		t.Fatalf("NewTrustedProxies: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		remote  string
+		tls     bool
+		headers map[string]string
+		want    string
+	}{
+		{
+			name: "direct", remote: "203.0.113.9:5000",
+			want: "203.0.113.9 http example.com",
+		},
+		{
+			name: "untrusted headers are ignored", remote: "203.0.113.9:5000",
+			headers: map[string]string{"X-Forwarded-For": "198.51.100.7", "X-Forwarded-Proto": "https"},
+			want:    "203.0.113.9 http example.com",
+		},
+		{
+			name: "direct tls", remote: "203.0.113.9:5000", tls: true,
+			want: "203.0.113.9 https example.com",
+		},
+		{
+			name: "x-forwarded", remote: "10.0.0.2:5000",
+			headers: map[string]string{
+				"X-Forwarded-For":   "198.51.100.7",
+				"X-Forwarded-Proto": "https",
+				"X-Forwarded-Host":  "api.example.com",
+			},
+			want: "198.51.100.7 https api.example.com",
+		},
+		{
+			name: "spoofed x-forwarded-for", remote: "10.0.0.2:5000",
+			headers: map[string]string{"X-Forwarded-For": "1.2.3.4, 198.51.100.7, 10.0.0.3"},
+			want:    "198.51.100.7 http example.com",
+		},
+		{
+			name: "forwarded", remote: "192.0.2.1:5000",
+			headers: map[string]string{
+				"Forwarded":       `for="[2001:db8::1]:4711";proto=https;host=api.example.com, for=10.0.0.3`,
+				"X-Forwarded-For": "1.2.3.4",
+			},
+			want: "2001:db8::1 https api.example.com",
+		},
+		{
+			name: "forwarded unknown node", remote: "10.0.0.2:5000",
+			headers: map[string]string{"Forwarded": "for=unknown;proto=https"},
+			want:    "10.0.0.2 http example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+			r.RemoteAddr = tt.remote
+
+			if tt.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+
+			var got string
+
+			proxies.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = ClientIP(r) + " " + Scheme(r) + " " + Host(r)
+			})).ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
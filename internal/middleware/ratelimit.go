@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/cs2-server/backend/internal/ratelimit"
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/cs2-server/backend/pkg/jwt"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ErrTooManyRequests = "too many requests"
+)
+
+type RateLimiter struct {
+	store  ratelimit.Store
+	rates  atomic.Pointer[map[string]ratelimit.Rate]
+	logger *logrus.Logger
+}
+
+// NewRateLimiter returns a limiter allowing rates by route group.
+func NewRateLimiter(store ratelimit.Store, rates map[string]ratelimit.Rate, logger *logrus.Logger) *RateLimiter {
+	l := &RateLimiter{
+		store:  store,
+		logger: logger,
+	}
+
+	l.SetRates(rates)
+
+	return l
+}
+
+// SetRates replaces the rates of every group. Clients keep the tokens they
+// have left, capped at the new limit.
+func (l *RateLimiter) SetRates(rates map[string]ratelimit.Rate) {
+	l.rates.Store(&rates)
+}
+
+// Limit returns a middleware that allows the group's rate of requests per
+// client. Clients are told apart by SteamID when the route is behind jwt.Auth
+// and by IP otherwise. Requests are let through if the group has no rate or
+// the store fails.
+func (l *RateLimiter) Limit(group string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rate, ok := (*l.rates.Load())[group]
+			if !ok {
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			key := group + ":ip:" + ClientIP(r)
+			if steamID, ok := jwt.SteamID(r.Context()); ok {
+				key = group + ":steam:" + steamID
+			}
+
+			res, err := l.store.Take(r.Context(), key, rate)
+			if err != nil {
+				l.logger.WithContext(r.Context()).Errorln("RateLimit (1):", err)
+				next.ServeHTTP(w, r)
+
+				return
+			}
+
+			w.Header().Set("RateLimit-Limit", strconv.Itoa(res.Limit))
+			w.Header().Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+			w.Header().Set("RateLimit-Reset", ceilSeconds(res.Reset))
+			w.Header().Set("RateLimit-Policy", strconv.Itoa(rate.Limit)+";w="+ceilSeconds(rate.Period))
+
+			if !res.Allowed {
+				l.logger.WithContext(r.Context()).Warnf("RateLimit (2): %s exceeded %s", key, rate)
+				w.Header().Set("Retry-After", ceilSeconds(res.RetryAfter))
+				render.Error(w, http.StatusTooManyRequests, ErrTooManyRequests)
+
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
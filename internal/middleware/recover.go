@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	ErrInternal = "internal server error"
+)
+
+type panicRecorder interface {
+	Panic()
+}
+
+// Recover turns a panic in next into a 500 with the usual JSON error body and
+// logs the stack. It must be the outermost handler so a panic in any other
+// middleware is caught too; those it wraps do not see the request end, so the
+// panic entry carries the method, path and request ID set by the access log.
+func Recover(logger *logrus.Logger, panics panicRecorder, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+
+			// net/http uses this panic to abort a response on purpose.
+			if err, ok := v.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(v)
+			}
+
+			panics.Panic()
+
+			logger.WithContext(r.Context()).WithFields(logrus.Fields{
+				"request_id": w.Header().Get(RequestIDHeader),
+				"method":     r.Method,
+				"path":       r.URL.Path,
+				"panic":      v,
+				"stack":      string(debug.Stack()),
+			}).Errorln("Recover: handler panicked")
+
+			// Too late for a proper error once the status line is out.
+			if rw.status != 0 {
+				panic(http.ErrAbortHandler)
+			}
+
+			render.Error(rw, http.StatusInternalServerError, ErrInternal)
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cs2-server/backend/internal/render"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+type panicCounter int
+
+func (c *panicCounter) Panic() {
+	*c++
+}
+
+func TestRecover(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	logger.AddHook(RequestIDHook{})
+
+	var panics panicCounter
+
+	handler := Recover(logger, &panics, NewAccessLog(logger).Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/servers", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+
+	var body render.Err
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil || body.Message != ErrInternal {
+		t.Errorf("body %+v, err %v", body, err)
+	}
+
+	if panics != 1 {
+		t.Errorf("panics counted %d, want 1", panics)
+	}
+
+	entries := hook.AllEntries()
+	if len(entries) != 1 {
+		t.Fatalf("got %d log entries, want 1", len(entries))
+	}
+
+	if entries[0].Data["request_id"] != w.Header().Get(RequestIDHeader) {
+		t.Errorf("panic entry request id %v, want %s", entries[0].Data["request_id"], w.Header().Get(RequestIDHeader))
+	}
+
+	if entries[0].Data["stack"] == "" {
+		t.Error("stack was not logged")
+	}
+
+	if entries[0].Data["path"] != "/api/servers" {
+		t.Errorf("panic entry path %v", entries[0].Data["path"])
+	}
+}
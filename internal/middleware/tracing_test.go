@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cs2-server/backend/internal/router"
+	"github.com/cs2-server/backend/internal/tracing/tracingtest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTrace(t *testing.T) {
+	recorder := tracingtest.NewRecorder(t)
+
+	routes := router.New()
+	routes.Group("/api").Get("/servers/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !trace.SpanContextFromContext(r.Context()).IsValid() {
+			t.Error("handler context carries no span")
+		}
+
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/api/servers/42", nil)
+	r.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+
+	Trace(routes, routes).ServeHTTP(httptest.NewRecorder(), r)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+
+	span := spans[0]
+
+	if span.Name() != "GET /api/servers/{id}" {
+		t.Errorf("span name %q", span.Name())
+	}
+
+	if got := span.Parent().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("trace was not continued, parent trace id %s", got)
+	}
+
+	if span.SpanKind() != trace.SpanKindServer {
+		t.Errorf("span kind %s", span.SpanKind())
+	}
+
+	var status int64
+	for _, attr := range span.Attributes() {
+		if attr.Key == semconv.HTTPResponseStatusCodeKey {
+			status = attr.Value.AsInt64()
+		}
+	}
+
+	if status != http.StatusInternalServerError {
+		t.Errorf("status attribute %d, want %d", status, http.StatusInternalServerError)
+	}
+}
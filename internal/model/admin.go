@@ -0,0 +1,95 @@
+package model
+
+import "strings"
+
+type Role string
+
+const (
+	RoleRoot      Role = "root"
+	RoleAdmin     Role = "admin"
+	RoleModerator Role = "moderator"
+)
+
+type Permission string
+
+const (
+	PermManageServers Permission = "servers.manage"
+	PermRCON          Permission = "servers.rcon"
+	PermManageBans    Permission = "bans.manage"
+)
+
+var RolePermissions = map[Role][]Permission{
+	RoleRoot:      {PermManageServers, PermRCON, PermManageBans},
+	RoleAdmin:     {PermManageServers, PermRCON, PermManageBans},
+	RoleModerator: {PermRCON, PermManageBans},
+}
+
+// RoleCommands lists the RCON commands each role may run. A trailing "*"
+// matches every command starting with the part before it.
+var RoleCommands = map[Role][]string{
+	RoleRoot: {"*"},
+	RoleAdmin: {
+		"status", "say", "kick*", "changelevel", "map", "mp_*", "bot_*",
+		"tv_*", "sv_password", "users", "listid", "banid", "removeid",
+	},
+	RoleModerator: {"status", "say", "kick*", "users"},
+}
+
+func (r Role) Valid() bool {
+	_, ok := RolePermissions[r]
+
+	return ok
+}
+
+func (r Role) Has(perm Permission) bool {
+	for _, p := range RolePermissions[r] {
+		if p == perm {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanRun reports whether every statement of an RCON command line is allowed
+// for the role.
+func (r Role) CanRun(command string) bool {
+	statements := strings.FieldsFunc(command, func(c rune) bool {
+		return c == ';' || c == '\n' || c == '\r'
+	})
+
+	ran := false
+
+	for _, st := range statements {
+		fields := strings.Fields(st)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if !r.canRun(strings.ToLower(fields[0])) {
+			return false
+		}
+
+		ran = true
+	}
+
+	return ran
+}
+
+func (r Role) canRun(name string) bool {
+	for _, allowed := range RoleCommands[r] {
+		if prefix, ok := strings.CutSuffix(allowed, "*"); ok {
+			if strings.HasPrefix(name, prefix) {
+				return true
+			}
+
+			continue
+		}
+
+		if name == allowed {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,29 @@
+package model
+
+import "testing"
+
+func TestRoleCanRun(t *testing.T) {
+	tests := []struct {
+		role    Role
+		command string
+		want    bool
+	}{
+		{RoleRoot, "rcon_password x", true},
+		{RoleAdmin, "mp_restartgame 1", true},
+		{RoleAdmin, "changelevel de_mirage", true},
+		{RoleAdmin, "rcon_password x", false},
+		{RoleAdmin, "say hi; rcon_password x", false},
+		{RoleAdmin, "say hi\nquit", false},
+		{RoleModerator, "kickid 5", true},
+		{RoleModerator, "KICK player", true},
+		{RoleModerator, "mp_restartgame 1", false},
+		{RoleModerator, " ; ", false},
+		{Role("unknown"), "status", false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.role.CanRun(tt.command); got != tt.want {
+			t.Errorf("%s.CanRun(%q) = %v, want %v", tt.role, tt.command, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type APIKeyScope string
+
+const (
+	ScopeBansCheck     APIKeyScope = "bans:check"
+	ScopeBansWrite     APIKeyScope = "bans:write"
+	ScopeEventsPublish APIKeyScope = "events:publish"
+)
+
+var APIKeyScopes = []APIKeyScope{ScopeBansCheck, ScopeBansWrite, ScopeEventsPublish}
+
+func (s APIKeyScope) Valid() bool {
+	for _, scope := range APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+type APIKey struct {
+	ID         int64         `json:"id" validate:"required"`
+	ServerID   int64         `json:"server_id" validate:"required"`
+	Name       string        `json:"name"`
+	Prefix     string        `json:"prefix" validate:"required"`
+	Scopes     []APIKeyScope `json:"scopes" validate:"required"`
+	CreatedAt  time.Time     `json:"created_at" validate:"required"`
+	LastUsedAt *time.Time    `json:"last_used_at,omitempty"`
+	LastUsedIP string        `json:"last_used_ip,omitempty"`
+	RevokedAt  *time.Time    `json:"revoked_at,omitempty"`
+}
+
+func (k APIKey) Has(scope APIKeyScope) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Actor is how actions done with the key show up in audit fields.
+func (k APIKey) Actor() string {
+	return fmt.Sprintf("server:%d", k.ServerID)
+}
+
+type APIKeyInput struct {
+	Name   string        `json:"name"`
+	Scopes []APIKeyScope `json:"scopes" validate:"required"`
+}
+
+func (i APIKeyInput) Validate() error {
+	if len(i.Scopes) == 0 {
+		return fmt.Errorf("%w: scopes are empty", ErrInvalidInput)
+	}
+
+	for _, scope := range i.Scopes {
+		if !scope.Valid() {
+			return fmt.Errorf("%w: unknown scope %q", ErrInvalidInput, scope)
+		}
+	}
+
+	return nil
+}
+
+// IssuedAPIKey carries the plain key, which is shown only once.
+type IssuedAPIKey struct {
+	APIKey
+	Key string `json:"key" validate:"required"`
+}
@@ -0,0 +1,84 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type BanType string
+
+const (
+	BanTypeBan  BanType = "ban"
+	BanTypeMute BanType = "mute"
+	BanTypeGag  BanType = "gag"
+)
+
+func (t BanType) Valid() bool {
+	switch t {
+	case BanTypeBan, BanTypeMute, BanTypeGag:
+		return true
+	}
+
+	return false
+}
+
+type Ban struct {
+	ID        int64      `json:"id" validate:"required"`
+	Type      BanType    `json:"type" validate:"required"`
+	SteamID   string     `json:"steam_id" validate:"required"`
+	IP        string     `json:"ip,omitempty"`
+	Reason    string     `json:"reason" validate:"required"`
+	AdminID   string     `json:"admin_id" validate:"required"`
+	Duration  int64      `json:"duration" validate:"required"`
+	CreatedAt time.Time  `json:"created_at" validate:"required"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Active    bool       `json:"active" validate:"required"`
+
+	LiftedAt   *time.Time `json:"lifted_at,omitempty"`
+	LiftedBy   string     `json:"lifted_by,omitempty"`
+	LiftReason string     `json:"lift_reason,omitempty"`
+}
+
+type BanInput struct {
+	Type    BanType `json:"type" validate:"required"`
+	SteamID string  `json:"steam_id" validate:"required"`
+	IP      string  `json:"ip,omitempty"`
+	Reason  string  `json:"reason" validate:"required"`
+	// Duration is in seconds, zero means permanent.
+	Duration int64 `json:"duration"`
+}
+
+func (i BanInput) Validate() error {
+	switch {
+	case !i.Type.Valid():
+		return fmt.Errorf("%w: unknown type %q", ErrInvalidInput, i.Type)
+	case i.SteamID == "":
+		return fmt.Errorf("%w: steam_id is empty", ErrInvalidInput)
+	case i.Reason == "":
+		return fmt.Errorf("%w: reason is empty", ErrInvalidInput)
+	case i.Duration < 0:
+		return fmt.Errorf("%w: duration is negative", ErrInvalidInput)
+	}
+
+	return nil
+}
+
+type BanLift struct {
+	Reason string `json:"reason" validate:"required"`
+}
+
+type BanFilter struct {
+	SteamID string
+	Type    BanType
+	Active  *bool
+	Limit   int
+	Offset  int
+}
+
+// BanCheck is what game servers get when a player connects.
+type BanCheck struct {
+	Banned bool  `json:"banned" validate:"required"`
+	Muted  bool  `json:"muted" validate:"required"`
+	Gagged bool  `json:"gagged" validate:"required"`
+	Bans   []Ban `json:"bans" validate:"required"`
+}
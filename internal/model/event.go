@@ -0,0 +1,113 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type Topic string
+
+const (
+	TopicKills   Topic = "kills"
+	TopicServers Topic = "servers"
+	TopicMatches Topic = "matches"
+	TopicBans    Topic = "bans"
+)
+
+var Topics = []Topic{TopicKills, TopicServers, TopicMatches, TopicBans}
+
+func (t Topic) Valid() bool {
+	for _, topic := range Topics {
+		if t == topic {
+			return true
+		}
+	}
+
+	return false
+}
+
+const (
+	EventKill         = "kill"
+	EventMatchStart   = "match_start"
+	EventMatchEnd     = "match_end"
+	EventRoundEnd     = "round_end"
+	EventServerStatus = "server_status"
+	EventBanCreated   = "ban_created"
+	EventBanLifted    = "ban_lifted"
+)
+
+type Event struct {
+	Topic Topic     `json:"topic" validate:"required"`
+	Type  string    `json:"type" validate:"required"`
+	Data  any       `json:"data" validate:"required"`
+	Time  time.Time `json:"time" validate:"required"`
+}
+
+type KillEvent struct {
+	ServerID   int64  `json:"server_id" validate:"required"`
+	KillerID   string `json:"killer_id" validate:"required"`
+	KillerName string `json:"killer_name" validate:"required"`
+	VictimID   string `json:"victim_id" validate:"required"`
+	VictimName string `json:"victim_name" validate:"required"`
+	Weapon     string `json:"weapon" validate:"required"`
+	Headshot   bool   `json:"headshot"`
+}
+
+type MatchEvent struct {
+	ServerID int64  `json:"server_id" validate:"required"`
+	Map      string `json:"map" validate:"required"`
+	ScoreCT  int    `json:"score_ct"`
+	ScoreT   int    `json:"score_t"`
+}
+
+type ServerStatusEvent struct {
+	ServerID int64        `json:"server_id" validate:"required"`
+	Status   ServerStatus `json:"status" validate:"required"`
+}
+
+// GameEventInput is what game servers post to the event stream.
+type GameEventInput struct {
+	Type string          `json:"type" validate:"required" enums:"kill,match_start,match_end"`
+	Data json.RawMessage `json:"data" validate:"required" swaggertype:"object"`
+}
+
+// Event validates the input and turns it into an event of the server.
+func (i GameEventInput) Event(serverID int64) (Event, error) {
+	var (
+		topic Topic
+		data  any
+	)
+
+	switch i.Type {
+	case EventKill:
+		var kill KillEvent
+		if err := json.Unmarshal(i.Data, &kill); err != nil {
+			return Event{}, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+
+		if kill.KillerID == "" || kill.VictimID == "" {
+			return Event{}, fmt.Errorf("%w: killer_id and victim_id are required", ErrInvalidInput)
+		}
+
+		kill.ServerID = serverID
+		topic, data = TopicKills, kill
+	case EventMatchStart, EventMatchEnd:
+		var match MatchEvent
+		if err := json.Unmarshal(i.Data, &match); err != nil {
+			return Event{}, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+
+		match.ServerID = serverID
+		topic, data = TopicMatches, match
+	default:
+		return Event{}, fmt.Errorf("%w: unknown event type %q", ErrInvalidInput, i.Type)
+	}
+
+	return Event{
+		Topic: topic,
+		Type:  i.Type,
+		Data:  data,
+		Time:  time.Now(),
+	}, nil
+}
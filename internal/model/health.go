@@ -0,0 +1,15 @@
+package model
+
+// HealthCheck is one dependency probed by readiness. Err is only logged, as
+// it can name hosts and other internals.
+type HealthCheck struct {
+	Name string `json:"name"`
+	OK   bool   `json:"ok"`
+	Err  error  `json:"-"`
+}
+
+type Readiness struct {
+	Ready    bool          `json:"ready"`
+	Draining bool          `json:"draining"`
+	Checks   []HealthCheck `json:"checks"`
+}
@@ -0,0 +1,108 @@
+package model
+
+import (
+	"fmt"
+	"time"
+)
+
+type Side string
+
+const (
+	SideCT Side = "ct"
+	SideT  Side = "t"
+)
+
+func (s Side) Valid() bool {
+	return s == SideCT || s == SideT
+}
+
+// RoundInput is what game servers report when a round ends.
+type RoundInput struct {
+	Map    string `json:"map" validate:"required"`
+	Winner Side   `json:"winner" validate:"required" enums:"ct,t"`
+	// Duration is in seconds.
+	Duration float64       `json:"duration" validate:"required"`
+	Players  []RoundPlayer `json:"players"`
+}
+
+// RoundPlayer is what a player did in the round.
+type RoundPlayer struct {
+	SteamID   string `json:"steam_id" validate:"required"`
+	Side      Side   `json:"side" validate:"required" enums:"ct,t"`
+	Kills     int    `json:"kills"`
+	Deaths    int    `json:"deaths"`
+	Headshots int    `json:"headshots"`
+}
+
+func (i RoundInput) Validate() error {
+	switch {
+	case i.Map == "":
+		return fmt.Errorf("%w: map is empty", ErrInvalidInput)
+	case !i.Winner.Valid():
+		return fmt.Errorf("%w: unknown winner %q", ErrInvalidInput, i.Winner)
+	case i.Duration <= 0:
+		return fmt.Errorf("%w: duration must be positive", ErrInvalidInput)
+	}
+
+	seen := make(map[string]bool, len(i.Players))
+
+	for _, p := range i.Players {
+		switch {
+		case p.SteamID == "":
+			return fmt.Errorf("%w: player steam_id is empty", ErrInvalidInput)
+		case seen[p.SteamID]:
+			return fmt.Errorf("%w: player %s is listed twice", ErrInvalidInput, p.SteamID)
+		case !p.Side.Valid():
+			return fmt.Errorf("%w: unknown side %q of %s", ErrInvalidInput, p.Side, p.SteamID)
+		case p.Kills < 0 || p.Deaths < 0 || p.Headshots < 0:
+			return fmt.Errorf("%w: negative counts for %s", ErrInvalidInput, p.SteamID)
+		case p.Headshots > p.Kills:
+			return fmt.Errorf("%w: more headshots than kills for %s", ErrInvalidInput, p.SteamID)
+		}
+
+		seen[p.SteamID] = true
+	}
+
+	return nil
+}
+
+type Round struct {
+	ID       int64     `json:"id" validate:"required"`
+	ServerID int64     `json:"server_id" validate:"required"`
+	Map      string    `json:"map" validate:"required"`
+	Winner   Side      `json:"winner" validate:"required"`
+	Duration float64   `json:"duration" validate:"required"`
+	EndedAt  time.Time `json:"ended_at" validate:"required"`
+}
+
+// MapStats aggregates the rounds played on a map. Win rates are percentages
+// and AvgRoundLength is in seconds.
+type MapStats struct {
+	Name           string    `json:"name" validate:"required"`
+	Rounds         int       `json:"rounds" validate:"required"`
+	CTWins         int       `json:"ct_wins" validate:"required"`
+	TWins          int       `json:"t_wins" validate:"required"`
+	CTWinRate      int       `json:"ct_win_rate" validate:"required"`
+	TWinRate       int       `json:"t_win_rate" validate:"required"`
+	AvgRoundLength float64   `json:"avg_round_length" validate:"required"`
+	LastPlayed     time.Time `json:"last_played" validate:"required"`
+}
+
+// MapPlayerStats are a player's totals on one map.
+type MapPlayerStats struct {
+	SteamID   string
+	Rounds    int
+	Kills     int
+	Deaths    int
+	Headshots int
+}
+
+type LeaderboardEntry struct {
+	Rank         int     `json:"rank" validate:"required"`
+	SteamID      string  `json:"steam_id" validate:"required"`
+	Rounds       int     `json:"rounds" validate:"required"`
+	Kills        int     `json:"kills" validate:"required"`
+	Deaths       int     `json:"deaths" validate:"required"`
+	KD           float64 `json:"kd" validate:"required"`
+	HeadshotRate int     `json:"headshot_rate" validate:"required"`
+}
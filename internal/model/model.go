@@ -23,6 +23,9 @@ type Profile struct {
 	Kills        int    `json:"kills" validate:"required"`
 	Deaths       int    `json:"deaths" validate:"required"`
 	HeadshotRate int    `json:"headshot_rate" validate:"required"`
+	// StatsUnavailable is set when the stats could not be read in time;
+	// kills, deaths and headshot rate are zero then.
+	StatsUnavailable bool `json:"stats_unavailable,omitempty"`
 }
 
 type Stats struct {
@@ -31,8 +34,16 @@ type Stats struct {
 	Headshots int
 }
 
+// Token types, so a refresh token cannot be used as an access token and
+// the other way round.
+const (
+	TokenAccess  = "access"
+	TokenRefresh = "refresh"
+)
+
 type JWTClaims struct {
-	ID string `json:"id"`
+	ID   string `json:"id"`
+	Type string `json:"typ"`
 	jwt.StandardClaims
 }
 
@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+type RCONRequest struct {
+	Command string `json:"command" validate:"required"`
+}
+
+type RCONResponse struct {
+	Output string `json:"output" validate:"required"`
+}
+
+type RCONLog struct {
+	ID        int64     `json:"id" validate:"required"`
+	ServerID  int64     `json:"server_id" validate:"required"`
+	SteamID   string    `json:"steam_id" validate:"required"`
+	Command   string    `json:"command" validate:"required"`
+	Output    string    `json:"output" validate:"required"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at" validate:"required"`
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+type Server struct {
+	ID        int64     `json:"id" validate:"required"`
+	Name      string    `json:"name" validate:"required"`
+	Host      string    `json:"host" validate:"required"`
+	Port      int       `json:"port" validate:"required"`
+	RCON      bool      `json:"rcon" validate:"required"`
+	CreatedAt time.Time `json:"created_at" validate:"required"`
+	UpdatedAt time.Time `json:"updated_at" validate:"required"`
+
+	RCONPassword string `json:"-"`
+}
+
+// Addr is the host and port to dial, with IPv6 hosts in brackets.
+func (s Server) Addr() string {
+	return net.JoinHostPort(s.Host, strconv.Itoa(s.Port))
+}
+
+type ServerInput struct {
+	Name string `json:"name" validate:"required"`
+	Host string `json:"host" validate:"required"`
+	Port int    `json:"port" validate:"required"`
+	// RCONPassword is left unchanged on update when empty.
+	RCONPassword string `json:"rcon_password,omitempty"`
+}
+
+func (i ServerInput) Validate() error {
+	switch {
+	case i.Name == "":
+		return fmt.Errorf("%w: name is empty", ErrInvalidInput)
+	case i.Host == "":
+		return fmt.Errorf("%w: host is empty", ErrInvalidInput)
+	case i.Port <= 0 || i.Port > 65535:
+		return fmt.Errorf("%w: port is out of range", ErrInvalidInput)
+	}
+
+	return nil
+}
+
+type ServerPlayer struct {
+	Name     string  `json:"name" validate:"required"`
+	Score    int     `json:"score" validate:"required"`
+	Duration float64 `json:"duration" validate:"required"`
+}
+
+type ServerStatus struct {
+	Online     bool           `json:"online" validate:"required"`
+	Map        string         `json:"map"`
+	Players    int            `json:"players"`
+	MaxPlayers int            `json:"max_players"`
+	Bots       int            `json:"bots"`
+	PlayerList []ServerPlayer `json:"player_list"`
+	CheckedAt  *time.Time     `json:"checked_at,omitempty"`
+}
+
+type ServerInfo struct {
+	Server
+	Status ServerStatus `json:"status" validate:"required"`
+}
@@ -0,0 +1,20 @@
+package model
+
+import "testing"
+
+func TestServerAddr(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"127.0.0.1", "127.0.0.1:27015"},
+		{"cs2.example.com", "cs2.example.com:27015"},
+		{"::1", "[::1]:27015"},
+	}
+
+	for _, tt := range tests {
+		if got := (Server{Host: tt.host, Port: 27015}).Addr(); got != tt.want {
+			t.Errorf("Addr of %s: got %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
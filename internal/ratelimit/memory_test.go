@@ -0,0 +1,78 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	rate := Rate{Limit: 3, Period: 3 * time.Second}
+
+	for i := 0; i < 3; i++ {
+		res, err := s.Take(context.Background(), "k", rate)
+		if err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+
+		if !res.Allowed {
+			t.Fatalf("request %d: not allowed", i)
+		}
+
+		if want := 2 - i; res.Remaining != want {
+			t.Errorf("request %d: remaining %d, want %d", i, res.Remaining, want)
+		}
+	}
+
+	res, _ := s.Take(context.Background(), "k", rate)
+	if res.Allowed {
+		t.Fatal("request over the limit allowed")
+	}
+
+	if res.RetryAfter != time.Second {
+		t.Errorf("retry after %s, want %s", res.RetryAfter, time.Second)
+	}
+
+	if res, _ := s.Take(context.Background(), "other", rate); !res.Allowed {
+		t.Error("other key shares the bucket")
+	}
+
+	now = now.Add(time.Second)
+
+	if res, _ := s.Take(context.Background(), "k", rate); !res.Allowed {
+		t.Error("bucket did not refill")
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Rate
+		wantErr bool
+	}{
+		{in: "10/1m", want: Rate{Limit: 10, Period: time.Minute}},
+		{in: "1/500ms", want: Rate{Limit: 1, Period: 500 * time.Millisecond}},
+		{in: "10", wantErr: true},
+		{in: "0/1m", wantErr: true},
+		{in: "10/0s", wantErr: true},
+		{in: "x/1m", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseRate(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseRate(%q): err %v, wantErr %v", tt.in, err, tt.wantErr)
+
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("ParseRate(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
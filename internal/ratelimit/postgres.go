@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+// PostgresStore keeps buckets in the rate_limits table so that every replica
+// shares them. Time is taken from the database to keep replicas with skewed
+// clocks consistent.
+type PostgresStore struct {
+	db     *pgxpool.Pool
+	logger *logrus.Logger
+}
+
+func NewPostgresStore(db *pgxpool.Pool, logger *logrus.Logger) *PostgresStore {
+	return &PostgresStore{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, rate Rate) (Result, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return Result{}, fmt.Errorf("Take (1): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	insert := `
+        INSERT INTO rate_limits (key, tokens, updated_at, expires_at)
+        VALUES ($1, $2, now(), now() + make_interval(secs => $3))
+        ON CONFLICT (key) DO NOTHING
+    `
+
+	if _, err := tx.Exec(ctx, insert, key, float64(rate.Limit), rate.Period.Seconds()); err != nil {
+		return Result{}, fmt.Errorf("Take (2): %w", err)
+	}
+
+	query := `
+        SELECT tokens, updated_at, now()
+        FROM rate_limits
+        WHERE key = $1
+        FOR UPDATE
+    `
+
+	var (
+		tokens       float64
+		updated, now time.Time
+	)
+
+	if err := tx.QueryRow(ctx, query, key).Scan(&tokens, &updated, &now); err != nil {
+		return Result{}, fmt.Errorf("Take (3): %w", err)
+	}
+
+	tokens, res := take(tokens, updated, now, rate)
+
+	update := `
+        UPDATE rate_limits
+        SET tokens = $2, updated_at = $3::timestamptz, expires_at = $3::timestamptz + make_interval(secs => $4)
+        WHERE key = $1
+    `
+
+	if _, err := tx.Exec(ctx, update, key, tokens, now, rate.Period.Seconds()); err != nil {
+		return Result{}, fmt.Errorf("Take (4): %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return Result{}, fmt.Errorf("Take (5): %w", err)
+	}
+
+	return res, nil
+}
+
+// Cleanup removes buckets that are full again.
+func (s *PostgresStore) Cleanup(ctx context.Context) error {
+	query := `
+        DELETE FROM rate_limits
+        WHERE expires_at < now()
+    `
+
+	if _, err := s.db.Exec(ctx, query); err != nil {
+		return fmt.Errorf("Cleanup: %w", err)
+	}
+
+	return nil
+}
+
+// Run calls Cleanup once per interval until ctx is done.
+func (s *PostgresStore) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.Cleanup(ctx); err != nil && ctx.Err() == nil {
+				s.logger.Errorln(err)
+			}
+		}
+	}
+}
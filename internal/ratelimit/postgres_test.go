@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/cs2-server/backend/internal/storage/storagetest"
+	"github.com/sirupsen/logrus"
+)
+
+func TestPostgresStore(t *testing.T) {
+	db := storagetest.New(t)
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	ctx := context.Background()
+	s := NewPostgresStore(db, logger)
+	rate := Rate{Limit: 3, Period: 3 * time.Second}
+
+	take := func(key string) Result {
+		t.Helper()
+
+		res, err := s.Take(ctx, key, rate)
+		if err != nil {
+			t.Fatalf("Take: %v", err)
+		}
+
+		return res
+	}
+
+	// rewind moves the bucket of key back in time, as if d had passed.
+	rewind := func(key string, d time.Duration) {
+		t.Helper()
+
+		query := `
+            UPDATE rate_limits
+            SET updated_at = updated_at - make_interval(secs => $2),
+                expires_at = expires_at - make_interval(secs => $2)
+            WHERE key = $1
+        `
+
+		if _, err := db.Exec(ctx, query, key, d.Seconds()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 3; i++ {
+		if res := take("k"); !res.Allowed || res.Remaining != 2-i {
+			t.Fatalf("request %d: %+v", i, res)
+		}
+	}
+
+	if res := take("k"); res.Allowed || res.RetryAfter <= 0 {
+		t.Fatalf("request over the limit: %+v", res)
+	}
+
+	if res := take("other"); !res.Allowed {
+		t.Error("other key shares the bucket")
+	}
+
+	rewind("k", time.Second)
+
+	if res := take("k"); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("after one token refilled: %+v", res)
+	}
+
+	// However long it sat unused, the bucket holds no more than the limit.
+	rewind("k", time.Hour)
+
+	if res := take("k"); !res.Allowed || res.Remaining != 2 {
+		t.Errorf("after a long pause: %+v", res)
+	}
+
+	rewind("other", time.Hour)
+
+	if err := s.Cleanup(ctx); err != nil {
+		t.Fatalf("Cleanup: %v", err)
+	}
+
+	var keys []string
+
+	rows, err := db.Query(ctx, "SELECT key FROM rate_limits ORDER BY key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			t.Fatal(err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 1 || keys[0] != "k" {
+		t.Errorf("buckets after cleanup %v, want [k]", keys)
+	}
+}
@@ -0,0 +1,88 @@
+// Package ratelimit implements token buckets kept in memory or in Postgres.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rate allows Limit requests per Period, refilling continuously. Limit is also
+// the burst size.
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+// ParseRate parses rates written as "10/1m": ten requests per minute.
+func ParseRate(s string) (Rate, error) {
+	limit, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("ParseRate (1): %q is not in limit/period form", s)
+	}
+
+	n, err := strconv.Atoi(limit)
+	if err != nil || n <= 0 {
+		return Rate{}, fmt.Errorf("ParseRate (2): invalid limit %q", limit)
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Rate{}, fmt.Errorf("ParseRate (3): invalid period %q", period)
+	}
+
+	return Rate{Limit: n, Period: d}, nil
+}
+
+func (r Rate) String() string {
+	return fmt.Sprintf("%d/%s", r.Limit, r.Period)
+}
+
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// RetryAfter is how long until the next request would be allowed, set
+	// only when the request is not allowed.
+	RetryAfter time.Duration
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+}
+
+type Store interface {
+	Take(ctx context.Context, key string, rate Rate) (Result, error)
+}
+
+// take refills a bucket holding tokens as of updated and takes one token from
+// it if there is one. It returns the tokens left.
+func take(tokens float64, updated, now time.Time, rate Rate) (float64, Result) {
+	limit := float64(rate.Limit)
+	perSecond := limit / rate.Period.Seconds()
+
+	if updated.IsZero() {
+		tokens = limit
+	} else if elapsed := now.Sub(updated).Seconds(); elapsed > 0 {
+		tokens = math.Min(limit, tokens+elapsed*perSecond)
+	}
+
+	res := Result{Limit: rate.Limit}
+
+	if tokens >= 1 {
+		tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - tokens) / perSecond)
+	}
+
+	res.Remaining = int(tokens)
+	res.Reset = seconds((limit - tokens) / perSecond)
+
+	return tokens, res
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,190 @@
+// Package router registers routes on a ServeMux in groups that share a path
+// prefix and a middleware chain, and keeps a table of every route.
+package router
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/cs2-server/backend/internal/render"
+)
+
+const (
+	ErrNotFound         = "not found"
+	ErrMethodNotAllowed = "method not allowed"
+)
+
+// Middleware wraps a handler. Nil middlewares are skipped, so optional ones
+// can be passed as they are.
+type Middleware func(http.HandlerFunc) http.HandlerFunc
+
+// Chain composes middlewares into one, the first one outermost.
+func Chain(chain ...Middleware) Middleware {
+	return func(h http.HandlerFunc) http.HandlerFunc {
+		return wrap(h, chain)
+	}
+}
+
+func wrap(h http.HandlerFunc, chain []Middleware) http.HandlerFunc {
+	for i := len(chain) - 1; i >= 0; i-- {
+		if chain[i] != nil {
+			h = chain[i](h)
+		}
+	}
+
+	return h
+}
+
+type Route struct {
+	Method string
+	Path   string
+	// Deprecated is set on routes kept only as aliases.
+	Deprecated bool
+}
+
+type Router struct {
+	mux    *http.ServeMux
+	routes []Route
+}
+
+func New() *Router {
+	return &Router{
+		mux: http.NewServeMux(),
+	}
+}
+
+// Group returns a group of routes under prefix, wrapped by chain.
+func (r *Router) Group(prefix string, chain ...Middleware) *Group {
+	return &Group{router: r, prefix: prefix, chain: chain}
+}
+
+// Handle registers a route outside of any group and chain.
+func (r *Router) Handle(method, path string, h http.Handler) {
+	r.mux.Handle(method+" "+path, h)
+	r.routes = append(r.routes, Route{Method: method, Path: path})
+}
+
+// Routes returns the table of registered routes in registration order.
+func (r *Router) Routes() []Route {
+	return slices.Clone(r.routes)
+}
+
+// Pattern returns the path of the route req matches, such as
+// /api/v1/servers/{id}, or an empty string if it matches none.
+func (r *Router) Pattern(req *http.Request) string {
+	_, pattern := r.mux.Handler(req)
+
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+
+	return pattern
+}
+
+// ServeHTTP answers requests that match no route with a JSON 404, or with a
+// 405 listing the allowed methods in Allow when the path exists.
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.Pattern(req) != "" {
+		r.mux.ServeHTTP(w, req)
+
+		return
+	}
+
+	allowed := r.allowed(req)
+	if len(allowed) == 0 {
+		render.Error(w, http.StatusNotFound, ErrNotFound)
+
+		return
+	}
+
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+
+	if req.Method == http.MethodOptions {
+		w.WriteHeader(http.StatusNoContent)
+
+		return
+	}
+
+	render.Error(w, http.StatusMethodNotAllowed, ErrMethodNotAllowed)
+}
+
+// allowed returns the methods the path of req is registered with.
+func (r *Router) allowed(req *http.Request) []string {
+	var methods []string
+
+	for _, method := range []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete,
+	} {
+		probe := req.Clone(req.Context())
+		probe.Method = method
+
+		if _, pattern := r.mux.Handler(probe); pattern != "" {
+			methods = append(methods, method)
+		}
+	}
+
+	if len(methods) > 0 {
+		methods = append(methods, http.MethodOptions)
+	}
+
+	return methods
+}
+
+type Group struct {
+	router     *Router
+	prefix     string
+	chain      []Middleware
+	deprecated bool
+}
+
+// Group returns a subgroup under g's prefix whose chain runs inside g's.
+func (g *Group) Group(prefix string, chain ...Middleware) *Group {
+	return &Group{
+		router:     g.router,
+		prefix:     g.prefix + prefix,
+		chain:      append(slices.Clone(g.chain), chain...),
+		deprecated: g.deprecated,
+	}
+}
+
+// With returns a group with the same prefix and chain extended.
+func (g *Group) With(chain ...Middleware) *Group {
+	return g.Group("", chain...)
+}
+
+// Deprecate returns a group whose routes are marked deprecated in the table.
+func (g *Group) Deprecate() *Group {
+	dg := g.Group("")
+	dg.deprecated = true
+
+	return dg
+}
+
+// Handle registers h for method and path under the group, wrapped by the
+// group's chain and then by chain, outermost first.
+func (g *Group) Handle(method, path string, h http.HandlerFunc, chain ...Middleware) {
+	h = wrap(wrap(h, chain), g.chain)
+
+	full := g.prefix + path
+
+	g.router.mux.HandleFunc(method+" "+full, h)
+	g.router.routes = append(g.router.routes, Route{Method: method, Path: full, Deprecated: g.deprecated})
+}
+
+func (g *Group) Get(path string, h http.HandlerFunc, chain ...Middleware) {
+	g.Handle(http.MethodGet, path, h, chain...)
+}
+
+func (g *Group) Post(path string, h http.HandlerFunc, chain ...Middleware) {
+	g.Handle(http.MethodPost, path, h, chain...)
+}
+
+func (g *Group) Put(path string, h http.HandlerFunc, chain ...Middleware) {
+	g.Handle(http.MethodPut, path, h, chain...)
+}
+
+func (g *Group) Delete(path string, h http.HandlerFunc, chain ...Middleware) {
+	g.Handle(http.MethodDelete, path, h, chain...)
+}
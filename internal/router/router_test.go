@@ -0,0 +1,100 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func mark(name string) Middleware {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("X-Chain", name)
+			next(w, r)
+		}
+	}
+}
+
+func TestGroupChain(t *testing.T) {
+	r := New()
+
+	api := r.Group("/api", mark("api"))
+	servers := api.Group("/servers", mark("servers"), nil)
+	servers.Get("/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.PathValue("id")))
+	}, mark("route"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/servers/7", nil))
+
+	if got := strings.Join(w.Header().Values("X-Chain"), ","); got != "api,servers,route" {
+		t.Errorf("chain order %q", got)
+	}
+
+	if w.Body.String() != "7" {
+		t.Errorf("body %q", w.Body.String())
+	}
+}
+
+func TestRouterFallback(t *testing.T) {
+	r := New()
+
+	g := r.Group("/api")
+	g.Get("/servers/{id}", func(w http.ResponseWriter, r *http.Request) {})
+	g.Delete("/servers/{id}", func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		method string
+		target string
+		status int
+		allow  string
+	}{
+		{http.MethodGet, "/api/servers/1", http.StatusOK, ""},
+		{http.MethodPost, "/api/servers/1", http.StatusMethodNotAllowed, "GET, HEAD, DELETE, OPTIONS"},
+		{http.MethodOptions, "/api/servers/1", http.StatusNoContent, "GET, HEAD, DELETE, OPTIONS"},
+		{http.MethodGet, "/api/maps", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.target, nil))
+
+		if w.Code != tt.status {
+			t.Errorf("%s %s: status %d, want %d", tt.method, tt.target, w.Code, tt.status)
+		}
+
+		if got := w.Header().Get("Allow"); got != tt.allow {
+			t.Errorf("%s %s: allow %q, want %q", tt.method, tt.target, got, tt.allow)
+		}
+	}
+}
+
+func TestRoutesTable(t *testing.T) {
+	r := New()
+
+	r.Handle(http.MethodGet, "/healthz", http.NotFoundHandler())
+	r.Group("/api/v1").Post("/bans", func(w http.ResponseWriter, r *http.Request) {})
+	r.Group("/api").Deprecate().Post("/bans", func(w http.ResponseWriter, r *http.Request) {})
+
+	want := []Route{
+		{Method: http.MethodGet, Path: "/healthz"},
+		{Method: http.MethodPost, Path: "/api/v1/bans"},
+		{Method: http.MethodPost, Path: "/api/bans", Deprecated: true},
+	}
+
+	got := r.Routes()
+	if len(got) != len(want) {
+		t.Fatalf("got %d routes, want %d", len(got), len(want))
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("route %d: %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if p := r.Pattern(httptest.NewRequest(http.MethodPost, "/api/v1/bans", nil)); p != "/api/v1/bans" {
+		t.Errorf("pattern %q", p)
+	}
+}
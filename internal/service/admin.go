@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	m "github.com/cs2-server/backend/internal/model"
+)
+
+type adminStorage interface {
+	GetRole(context.Context, string) (m.Role, error)
+}
+
+type AdminService struct {
+	storage adminStorage
+}
+
+func NewAdminService(storage adminStorage) *AdminService {
+	return &AdminService{
+		storage: storage,
+	}
+}
+
+func (s *AdminService) HasPermission(ctx context.Context, steamID string, perm m.Permission) (bool, error) {
+	role, err := s.storage.GetRole(ctx, steamID)
+	if err != nil {
+		if errors.Is(err, m.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("HasPermission: %w", err)
+	}
+
+	return role.Has(perm), nil
+}
@@ -2,69 +2,141 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/tracing"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/net/context"
 )
 
+var tracer = tracing.Tracer("github.com/cs2-server/backend/internal/service")
+
+//go:generate mockgen -source=auth.go -destination=auth_mock_test.go -package=service
+
 type authStorage interface {
 	GetProfileStatsByID(context.Context, string) (m.Stats, error)
 }
 
+type steamObserver interface {
+	ObserveSteam(method string, d time.Duration, err error)
+}
+
+// AuthService reads player summaries from the Steam Web API at steamURL,
+// which tests point at a stub.
 type AuthService struct {
-	storage authStorage
+	storage  authStorage
+	steamURL string
+	steam    steamObserver
 }
 
-func NewAuthService(storage authStorage) *AuthService {
+func NewAuthService(storage authStorage, steamURL string, steam steamObserver) *AuthService {
 	return &AuthService{
-		storage: storage,
+		storage:  storage,
+		steamURL: strings.TrimSuffix(steamURL, "/"),
+		steam:    steam,
 	}
 }
 
 func (s *AuthService) GetProfile(ctx context.Context, apiKey string, ID string) (m.Profile, error) {
-	url := fmt.Sprintf("http://api.steampowered.com/ISteamUser/GetPlayerSummaries/v0002/?key=%s&steamids=%s", apiKey, ID)
+	rawURL := fmt.Sprintf("%s/ISteamUser/GetPlayerSummaries/v0002/?key=%s&steamids=%s",
+		s.steamURL, url.QueryEscape(apiKey), url.QueryEscape(ID))
 
-	r, err := http.Get(url)
-	if err != nil {
-		return m.Profile{}, fmt.Errorf("GetProfile (1): %w", err)
-	}
-	defer r.Body.Close()
+	start := time.Now()
+
+	data, err := getPlayerSummaries(ctx, rawURL)
+	s.steam.ObserveSteam("GetPlayerSummaries", time.Since(start), err)
 
-	data, err := io.ReadAll(r.Body)
 	if err != nil {
-		return m.Profile{}, fmt.Errorf("GetProfile (2): %w", err)
+		return m.Profile{}, fmt.Errorf("GetProfile (1): %w", err)
 	}
 
 	var player m.PlayerResponse
 	err = json.Unmarshal(data, &player)
 	if err != nil {
-		return m.Profile{}, fmt.Errorf("GetProfile (3): %w", err)
+		return m.Profile{}, fmt.Errorf("GetProfile (2): %w", err)
 	}
 
 	if len(player.Response.Players) == 0 {
-		return m.Profile{}, fmt.Errorf("GetProfile (4): %w", err)
+		return m.Profile{}, fmt.Errorf("GetProfile (3): no steam player %s: %w", ID, m.ErrNotFound)
 	}
 
 	p := player.Response.Players[0]
 
+	profile := m.Profile{
+		ID:     p.ID,
+		Name:   p.Name,
+		URL:    p.URL,
+		Avatar: p.Avatar,
+	}
+
+	// A slow or failing database degrades the profile to what Steam knows
+	// rather than failing it.
 	stats, err := s.storage.GetProfileStatsByID(ctx, ID)
+	if errors.Is(err, m.ErrUnavailable) {
+		profile.StatsUnavailable = true
+
+		return profile, nil
+	}
+
+	if err != nil {
+		return m.Profile{}, fmt.Errorf("GetProfile (4): %w", err)
+	}
+
+	profile.Kills = stats.Kills
+	profile.Deaths = stats.Deaths
+	profile.HeadshotRate = countHeadshotRate(stats.Kills, stats.Headshots)
+
+	return profile, nil
+}
+
+// getPlayerSummaries fetches rawURL. The URL carries the API key, so it is
+// stripped from errors before they are recorded or returned.
+func getPlayerSummaries(ctx context.Context, rawURL string) (data []byte, err error) {
+	ctx, span := tracer.Start(ctx, "steam GetPlayerSummaries", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = fmt.Errorf("steam api %s: %w", urlErr.Op, urlErr.Err)
+		}
+
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		span.End()
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
-		return m.Profile{}, fmt.Errorf("GetProfile (5): %w", err)
+		return nil, err
+	}
+
+	span.SetAttributes(semconv.ServerAddress(req.URL.Hostname()))
+
+	r, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	span.SetAttributes(semconv.HTTPResponseStatusCode(r.StatusCode))
+
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("steam api responded with %s", r.Status)
 	}
 
-	return m.Profile{
-		ID:           p.ID,
-		Name:         p.Name,
-		URL:          p.URL,
-		Avatar:       p.Avatar,
-		Kills:        stats.Kills,
-		Deaths:       stats.Deaths,
-		HeadshotRate: countHeadshotRate(stats.Kills, stats.Headshots),
-	}, nil
+	return io.ReadAll(r.Body)
 }
 
 func countHeadshotRate(kills int, headshots int) int {
@@ -0,0 +1,92 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: auth.go
+//
+// Generated by this command:
+//
+//	mockgen -source=auth.go -destination=auth_mock_test.go -package=service
+//
+
+// Package service is a generated GoMock package.
+package service
+
+import (
+	reflect "reflect"
+	time "time"
+
+	model "github.com/cs2-server/backend/internal/model"
+	gomock "go.uber.org/mock/gomock"
+	context "golang.org/x/net/context"
+)
+
+// MockauthStorage is a mock of authStorage interface.
+type MockauthStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockauthStorageMockRecorder
+}
+
+// MockauthStorageMockRecorder is the mock recorder for MockauthStorage.
+type MockauthStorageMockRecorder struct {
+	mock *MockauthStorage
+}
+
+// NewMockauthStorage creates a new mock instance.
+func NewMockauthStorage(ctrl *gomock.Controller) *MockauthStorage {
+	mock := &MockauthStorage{ctrl: ctrl}
+	mock.recorder = &MockauthStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockauthStorage) EXPECT() *MockauthStorageMockRecorder {
+	return m.recorder
+}
+
+// GetProfileStatsByID mocks base method.
+func (m *MockauthStorage) GetProfileStatsByID(arg0 context.Context, arg1 string) (model.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfileStatsByID", arg0, arg1)
+	ret0, _ := ret[0].(model.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfileStatsByID indicates an expected call of GetProfileStatsByID.
+func (mr *MockauthStorageMockRecorder) GetProfileStatsByID(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfileStatsByID", reflect.TypeOf((*MockauthStorage)(nil).GetProfileStatsByID), arg0, arg1)
+}
+
+// MocksteamObserver is a mock of steamObserver interface.
+type MocksteamObserver struct {
+	ctrl     *gomock.Controller
+	recorder *MocksteamObserverMockRecorder
+}
+
+// MocksteamObserverMockRecorder is the mock recorder for MocksteamObserver.
+type MocksteamObserverMockRecorder struct {
+	mock *MocksteamObserver
+}
+
+// NewMocksteamObserver creates a new mock instance.
+func NewMocksteamObserver(ctrl *gomock.Controller) *MocksteamObserver {
+	mock := &MocksteamObserver{ctrl: ctrl}
+	mock.recorder = &MocksteamObserverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksteamObserver) EXPECT() *MocksteamObserverMockRecorder {
+	return m.recorder
+}
+
+// ObserveSteam mocks base method.
+func (m *MocksteamObserver) ObserveSteam(method string, d time.Duration, err error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "ObserveSteam", method, d, err)
+}
+
+// ObserveSteam indicates an expected call of ObserveSteam.
+func (mr *MocksteamObserverMockRecorder) ObserveSteam(method, d, err any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveSteam", reflect.TypeOf((*MocksteamObserver)(nil).ObserveSteam), method, d, err)
+}
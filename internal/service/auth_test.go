@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cs2-server/backend/internal/breaker"
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/service/steamtest"
+	"github.com/cs2-server/backend/internal/storage"
+	"github.com/cs2-server/backend/internal/storage/storagetest"
+	"github.com/cs2-server/backend/internal/tracing/tracingtest"
+	"go.opentelemetry.io/otel/codes"
+	"go.uber.org/mock/gomock"
+)
+
+// errAny stands for any error in test tables.
+var errAny = errors.New("any error")
+
+type noopSteam struct{}
+
+func (noopSteam) ObserveSteam(string, time.Duration, error) {}
+
+func TestGetProfile(t *testing.T) {
+	noPlayers := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"response":{"players":[]}}`))
+	}))
+	defer noPlayers.Close()
+
+	steam := steamtest.NewServer(t)
+	player := steamtest.Player(storagetest.PlayerID)
+
+	tests := []struct {
+		name     string
+		steamURL string
+		key      string
+		stats    m.Stats
+		err      error
+		skipDB   bool
+		want     m.Profile
+		wantErr  error
+	}{
+		{
+			name:     "steam rejects key",
+			steamURL: steam.URL,
+			key:      "wrong",
+			skipDB:   true,
+			wantErr:  errAny,
+		},
+		{
+			name:     "unknown player",
+			steamURL: noPlayers.URL,
+			key:      steamtest.Key,
+			skipDB:   true,
+			wantErr:  m.ErrNotFound,
+		},
+		{
+			name:     "stats unavailable",
+			steamURL: steam.URL,
+			key:      steamtest.Key,
+			err:      fmt.Errorf("GetProfileStats: %w", m.ErrUnavailable),
+			want:     m.Profile{ID: player.ID, Name: player.Name, URL: player.URL, Avatar: player.Avatar, StatsUnavailable: true},
+		},
+		{
+			name:     "storage error",
+			steamURL: steam.URL,
+			key:      steamtest.Key,
+			err:      errors.New("db"),
+			wantErr:  errAny,
+		},
+		{
+			name:     "ok",
+			steamURL: steam.URL,
+			key:      steamtest.Key,
+			stats:    m.Stats{Kills: 3, Deaths: 4, Headshots: 2},
+			want:     m.Profile{ID: player.ID, Name: player.Name, URL: player.URL, Avatar: player.Avatar, Kills: 3, Deaths: 4, HeadshotRate: 67},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+
+			storage := NewMockauthStorage(ctrl)
+			if !tt.skipDB {
+				storage.EXPECT().
+					GetProfileStatsByID(gomock.Any(), storagetest.PlayerID).
+					Return(tt.stats, tt.err)
+			}
+
+			observer := NewMocksteamObserver(ctrl)
+			observer.EXPECT().ObserveSteam("GetPlayerSummaries", gomock.Any(), gomock.Any())
+
+			s := NewAuthService(storage, tt.steamURL, observer)
+
+			profile, err := s.GetProfile(context.Background(), tt.key, storagetest.PlayerID)
+
+			switch {
+			case tt.wantErr == errAny:
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got %v, want %v", err, tt.wantErr)
+				}
+			case err != nil:
+				t.Fatalf("GetProfile: %v", err)
+			}
+
+			if profile != tt.want {
+				t.Fatalf("got %+v, want %+v", profile, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetProfileIntegration(t *testing.T) {
+	db := storagetest.New(t)
+	storagetest.Load(t, db)
+
+	steam := steamtest.NewServer(t)
+	s := NewAuthService(storage.NewAuthStorage(db, time.Second, breaker.New(5, time.Minute)), steam.URL, noopSteam{})
+
+	profile, err := s.GetProfile(context.Background(), steamtest.Key, storagetest.PlayerID)
+	if err != nil {
+		t.Fatalf("GetProfile: %v", err)
+	}
+
+	player := steamtest.Player(storagetest.PlayerID)
+	want := m.Profile{
+		ID:           player.ID,
+		Name:         player.Name,
+		URL:          player.URL,
+		Avatar:       player.Avatar,
+		Kills:        120,
+		Deaths:       80,
+		HeadshotRate: 40,
+	}
+	if profile != want {
+		t.Fatalf("got %+v, want %+v", profile, want)
+	}
+
+	if _, err := s.GetProfile(context.Background(), "wrong", storagetest.PlayerID); err == nil {
+		t.Fatal("a rejected Steam key must fail the profile")
+	}
+}
+
+func TestGetPlayerSummariesSpan(t *testing.T) {
+	recorder := tracingtest.NewRecorder(t)
+
+	steam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer steam.Close()
+
+	ctx, parent := tracer.Start(context.Background(), "parent")
+
+	if _, err := getPlayerSummaries(ctx, steam.URL); err == nil {
+		t.Fatal("expected error for 503 response")
+	}
+
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+
+	span := spans[0]
+
+	if span.Name() != "steam GetPlayerSummaries" {
+		t.Errorf("span name %q", span.Name())
+	}
+
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Error("steam span is not a child of the request span")
+	}
+
+	if span.Status().Code != codes.Error {
+		t.Errorf("span status %v, want error", span.Status().Code)
+	}
+
+	// Nothing listens on the port, so the client fails with a *url.Error,
+	// whose message would carry the key in the URL.
+	_, err := getPlayerSummaries(context.Background(), "http://127.0.0.1:1/?key=secret-steam-key")
+	if err == nil {
+		t.Fatal("expected error for unreachable steam")
+	}
+
+	if strings.Contains(err.Error(), "secret-steam-key") {
+		t.Errorf("error contains the key: %v", err)
+	}
+
+	span = recorder.Ended()[2]
+	if strings.Contains(span.Status().Description, "secret-steam-key") {
+		t.Errorf("span status contains the key: %s", span.Status().Description)
+	}
+
+	for _, ev := range span.Events() {
+		for _, attr := range ev.Attributes {
+			if strings.Contains(attr.Value.Emit(), "secret-steam-key") {
+				t.Errorf("span event %s contains the key", ev.Name)
+			}
+		}
+	}
+}
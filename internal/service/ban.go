@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	m "github.com/cs2-server/backend/internal/model"
+)
+
+const (
+	defaultBanLimit = 50
+	maxBanLimit     = 500
+)
+
+type banStorage interface {
+	CreateBan(context.Context, string, m.BanInput) (m.Ban, error)
+	GetBan(context.Context, int64) (m.Ban, error)
+	ListBans(context.Context, m.BanFilter) ([]m.Ban, error)
+	FindActiveBans(context.Context, string, string) ([]m.Ban, error)
+	LiftBan(context.Context, int64, string, string) (m.Ban, error)
+}
+
+type BanService struct {
+	storage banStorage
+	events  eventPublisher
+}
+
+func NewBanService(storage banStorage, events eventPublisher) *BanService {
+	return &BanService{
+		storage: storage,
+		events:  events,
+	}
+}
+
+func (s *BanService) CreateBan(ctx context.Context, adminID string, input m.BanInput) (m.Ban, error) {
+	if err := input.Validate(); err != nil {
+		return m.Ban{}, fmt.Errorf("CreateBan (1): %w", err)
+	}
+
+	ban, err := s.storage.CreateBan(ctx, adminID, input)
+	if err != nil {
+		return m.Ban{}, fmt.Errorf("CreateBan (2): %w", err)
+	}
+
+	s.publish(m.EventBanCreated, ban)
+
+	return ban, nil
+}
+
+func (s *BanService) GetBan(ctx context.Context, ID int64) (m.Ban, error) {
+	ban, err := s.storage.GetBan(ctx, ID)
+	if err != nil {
+		return m.Ban{}, fmt.Errorf("GetBan: %w", err)
+	}
+
+	return ban, nil
+}
+
+func (s *BanService) ListBans(ctx context.Context, filter m.BanFilter) ([]m.Ban, error) {
+	if filter.Type != "" && !filter.Type.Valid() {
+		return nil, fmt.Errorf("ListBans (1): %w: unknown type %q", m.ErrInvalidInput, filter.Type)
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultBanLimit
+	}
+
+	if filter.Limit > maxBanLimit {
+		filter.Limit = maxBanLimit
+	}
+
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	bans, err := s.storage.ListBans(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("ListBans (2): %w", err)
+	}
+
+	return bans, nil
+}
+
+func (s *BanService) LiftBan(ctx context.Context, ID int64, adminID, reason string) (m.Ban, error) {
+	if reason == "" {
+		return m.Ban{}, fmt.Errorf("LiftBan (1): %w: reason is empty", m.ErrInvalidInput)
+	}
+
+	ban, err := s.storage.LiftBan(ctx, ID, adminID, reason)
+	if err != nil {
+		return m.Ban{}, fmt.Errorf("LiftBan (2): %w", err)
+	}
+
+	s.publish(m.EventBanLifted, ban)
+
+	return ban, nil
+}
+
+// CheckPlayer reports the punishments in force for a connecting player.
+func (s *BanService) CheckPlayer(ctx context.Context, steamID, ip string) (m.BanCheck, error) {
+	if steamID == "" {
+		return m.BanCheck{}, fmt.Errorf("CheckPlayer (1): %w: steam_id is empty", m.ErrInvalidInput)
+	}
+
+	bans, err := s.storage.FindActiveBans(ctx, steamID, ip)
+	if err != nil {
+		return m.BanCheck{}, fmt.Errorf("CheckPlayer (2): %w", err)
+	}
+
+	check := m.BanCheck{Bans: bans}
+	for _, ban := range bans {
+		switch ban.Type {
+		case m.BanTypeBan:
+			check.Banned = true
+		case m.BanTypeMute:
+			check.Muted = true
+		case m.BanTypeGag:
+			check.Gagged = true
+		}
+	}
+
+	return check, nil
+}
+
+func (s *BanService) publish(typ string, ban m.Ban) {
+	// Subscribers are not necessarily admins.
+	ban.IP = ""
+
+	s.events.Publish(m.Event{
+		Topic: m.TopicBans,
+		Type:  typ,
+		Data:  ban,
+	})
+}
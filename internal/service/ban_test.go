@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	m "github.com/cs2-server/backend/internal/model"
+)
+
+type fakeBanStorage struct {
+	banStorage
+	active []m.Ban
+	lifted []int64
+}
+
+func (s *fakeBanStorage) FindActiveBans(context.Context, string, string) ([]m.Ban, error) {
+	return s.active, nil
+}
+
+func (s *fakeBanStorage) LiftBan(_ context.Context, ID int64, adminID, reason string) (m.Ban, error) {
+	s.lifted = append(s.lifted, ID)
+
+	return m.Ban{ID: ID, IP: "203.0.113.7", LiftedBy: adminID, LiftReason: reason}, nil
+}
+
+func TestCheckPlayer(t *testing.T) {
+	tests := []struct {
+		name    string
+		steamID string
+		active  []m.Ban
+		want    m.BanCheck
+		wantErr error
+	}{
+		{name: "empty steam id", wantErr: m.ErrInvalidInput},
+		{name: "clean", steamID: "1", want: m.BanCheck{}},
+		{
+			name:    "banned",
+			steamID: "1",
+			active:  []m.Ban{{Type: m.BanTypeBan}},
+			want:    m.BanCheck{Banned: true},
+		},
+		{
+			name:    "muted and gagged",
+			steamID: "1",
+			active:  []m.Ban{{Type: m.BanTypeMute}, {Type: m.BanTypeGag}},
+			want:    m.BanCheck{Muted: true, Gagged: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewBanService(&fakeBanStorage{active: tt.active}, &fakePublisher{})
+
+			got, err := s.CheckPlayer(context.Background(), tt.steamID, "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err %v, want %v", err, tt.wantErr)
+			}
+
+			if got.Banned != tt.want.Banned || got.Muted != tt.want.Muted || got.Gagged != tt.want.Gagged {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+
+			if len(got.Bans) != len(tt.active) {
+				t.Errorf("got %d bans, want %d", len(got.Bans), len(tt.active))
+			}
+		})
+	}
+}
+
+func TestLiftBan(t *testing.T) {
+	storage := &fakeBanStorage{}
+	events := &fakePublisher{}
+	s := NewBanService(storage, events)
+
+	if _, err := s.LiftBan(context.Background(), 1, "admin", ""); !errors.Is(err, m.ErrInvalidInput) {
+		t.Fatalf("empty reason: got %v", err)
+	}
+
+	if len(storage.lifted) != 0 || len(events.events) != 0 {
+		t.Fatal("ban lifted without a reason")
+	}
+
+	ban, err := s.LiftBan(context.Background(), 1, "admin", "appeal")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ban.LiftReason != "appeal" || ban.IP == "" {
+		t.Errorf("got %+v", ban)
+	}
+
+	if len(events.events) != 1 || events.events[0].Type != m.EventBanLifted {
+		t.Fatalf("events %+v", events.events)
+	}
+
+	if published := events.events[0].Data.(m.Ban); published.IP != "" {
+		t.Errorf("published ban has ip %q", published.IP)
+	}
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	m "github.com/cs2-server/backend/internal/model"
+)
+
+const (
+	SteamHealthPath = "/ISteamWebAPIUtil/GetServerInfo/v1/"
+
+	healthCheckTimeout = 2 * time.Second
+)
+
+type healthStorage interface {
+	Ping(context.Context) error
+	MigrationVersion(context.Context) (uint, bool, error)
+}
+
+// steamCheck remembers the last Steam result so probes do not hit Steam on
+// every call.
+type steamCheck struct {
+	url      string
+	interval time.Duration
+	client   *http.Client
+
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+type HealthService struct {
+	storage    healthStorage
+	migrations uint
+	steam      *steamCheck
+	draining   atomic.Bool
+}
+
+// NewHealthService checks that the schema is at migration version
+// migrations. Steam is checked at most once per steamInterval, and not at all
+// if steamURL is empty.
+func NewHealthService(storage healthStorage, migrations uint, steamURL string, steamInterval time.Duration) *HealthService {
+	s := &HealthService{
+		storage:    storage,
+		migrations: migrations,
+	}
+
+	if steamURL != "" {
+		s.steam = &steamCheck{
+			url:      steamURL,
+			interval: steamInterval,
+			client:   &http.Client{Timeout: healthCheckTimeout},
+		}
+	}
+
+	return s
+}
+
+// Drain makes the service report not ready from now on, so load balancers
+// stop sending traffic before the server shuts down.
+func (s *HealthService) Drain() {
+	s.draining.Store(true)
+}
+
+func (s *HealthService) Ready(ctx context.Context) m.Readiness {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	res := m.Readiness{
+		Draining: s.draining.Load(),
+		Checks: []m.HealthCheck{
+			healthCheck("postgres", s.storage.Ping(ctx)),
+			healthCheck("migrations", s.checkMigrations(ctx)),
+		},
+	}
+
+	if s.steam != nil {
+		res.Checks = append(res.Checks, healthCheck("steam", s.steam.check(ctx)))
+	}
+
+	res.Ready = !res.Draining
+	for _, c := range res.Checks {
+		res.Ready = res.Ready && c.OK
+	}
+
+	return res
+}
+
+func (s *HealthService) checkMigrations(ctx context.Context) error {
+	version, dirty, err := s.storage.MigrationVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		return fmt.Errorf("migration %d failed and left the schema dirty", version)
+	}
+
+	if version < s.migrations {
+		return fmt.Errorf("schema is at version %d, %d is required", version, s.migrations)
+	}
+
+	return nil
+}
+
+func (c *steamCheck) check(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.checkedAt.IsZero() && time.Since(c.checkedAt) < c.interval {
+		return c.err
+	}
+
+	c.err = c.request(ctx)
+	c.checkedAt = time.Now()
+
+	return c.err
+}
+
+func (c *steamCheck) request(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+
+	r, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+
+	if r.StatusCode != http.StatusOK {
+		return fmt.Errorf("steam api responded with %s", r.Status)
+	}
+
+	return nil
+}
+
+func healthCheck(name string, err error) m.HealthCheck {
+	return m.HealthCheck{Name: name, OK: err == nil, Err: err}
+}
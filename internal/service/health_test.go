@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeHealthStorage struct {
+	pingErr error
+	version uint
+	dirty   bool
+}
+
+func (f fakeHealthStorage) Ping(context.Context) error {
+	return f.pingErr
+}
+
+func (f fakeHealthStorage) MigrationVersion(context.Context) (uint, bool, error) {
+	return f.version, f.dirty, nil
+}
+
+func TestHealthServiceReady(t *testing.T) {
+	tests := []struct {
+		name    string
+		storage fakeHealthStorage
+		drain   bool
+		want    bool
+	}{
+		{name: "ready", storage: fakeHealthStorage{version: 6}, want: true},
+		{name: "newer schema", storage: fakeHealthStorage{version: 7}, want: true},
+		{name: "postgres down", storage: fakeHealthStorage{version: 6, pingErr: errors.New("down")}},
+		{name: "missing migrations", storage: fakeHealthStorage{version: 5}},
+		{name: "dirty schema", storage: fakeHealthStorage{version: 6, dirty: true}},
+		{name: "draining", storage: fakeHealthStorage{version: 6}, drain: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewHealthService(tt.storage, 6, "", 0)
+			if tt.drain {
+				s.Drain()
+			}
+
+			res := s.Ready(context.Background())
+			if res.Ready != tt.want {
+				t.Errorf("ready = %v, want %v: %+v", res.Ready, tt.want, res)
+			}
+
+			if res.Draining != tt.drain {
+				t.Errorf("draining = %v, want %v", res.Draining, tt.drain)
+			}
+		})
+	}
+}
+
+func TestHealthServiceSteamCached(t *testing.T) {
+	var hits atomic.Int32
+
+	steam := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer steam.Close()
+
+	s := NewHealthService(fakeHealthStorage{version: 6}, 6, steam.URL, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		if res := s.Ready(context.Background()); res.Ready {
+			t.Fatalf("ready with steam unreachable: %+v", res)
+		}
+	}
+
+	if n := hits.Load(); n != 1 {
+		t.Errorf("steam was called %d times, want 1", n)
+	}
+}
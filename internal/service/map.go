@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	m "github.com/cs2-server/backend/internal/model"
+)
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+type mapStorage interface {
+	RecordRound(context.Context, int64, m.RoundInput) (m.Round, error)
+	ListMaps(context.Context) ([]m.MapStats, error)
+	GetLeaderboard(context.Context, string, int) ([]m.MapPlayerStats, error)
+}
+
+type MapService struct {
+	storage mapStorage
+	events  eventPublisher
+}
+
+func NewMapService(storage mapStorage, events eventPublisher) *MapService {
+	return &MapService{
+		storage: storage,
+		events:  events,
+	}
+}
+
+// RecordRound stores a round reported by a game server and announces it on
+// the matches topic.
+func (s *MapService) RecordRound(ctx context.Context, serverID int64, input m.RoundInput) (m.Round, error) {
+	if err := input.Validate(); err != nil {
+		return m.Round{}, fmt.Errorf("RecordRound (1): %w", err)
+	}
+
+	round, err := s.storage.RecordRound(ctx, serverID, input)
+	if err != nil {
+		return m.Round{}, fmt.Errorf("RecordRound (2): %w", err)
+	}
+
+	s.events.Publish(m.Event{
+		Topic: m.TopicMatches,
+		Type:  m.EventRoundEnd,
+		Data:  round,
+	})
+
+	return round, nil
+}
+
+func (s *MapService) ListMaps(ctx context.Context) ([]m.MapStats, error) {
+	maps, err := s.storage.ListMaps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListMaps: %w", err)
+	}
+
+	for i := range maps {
+		maps[i].CTWinRate = percent(maps[i].CTWins, maps[i].Rounds)
+		maps[i].TWinRate = percent(maps[i].TWins, maps[i].Rounds)
+		maps[i].AvgRoundLength = math.Round(maps[i].AvgRoundLength*10) / 10
+	}
+
+	return maps, nil
+}
+
+// Leaderboard ranks the players of a map by kills. A map nobody has played
+// is not found.
+func (s *MapService) Leaderboard(ctx context.Context, mapName string, limit int) ([]m.LeaderboardEntry, error) {
+	if limit <= 0 {
+		limit = defaultLeaderboardLimit
+	}
+
+	if limit > maxLeaderboardLimit {
+		limit = maxLeaderboardLimit
+	}
+
+	players, err := s.storage.GetLeaderboard(ctx, mapName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("Leaderboard (1): %w", err)
+	}
+
+	if len(players) == 0 {
+		return nil, fmt.Errorf("Leaderboard (2): map %q: %w", mapName, m.ErrNotFound)
+	}
+
+	entries := make([]m.LeaderboardEntry, len(players))
+	for i, p := range players {
+		entries[i] = m.LeaderboardEntry{
+			Rank:         i + 1,
+			SteamID:      p.SteamID,
+			Rounds:       p.Rounds,
+			Kills:        p.Kills,
+			Deaths:       p.Deaths,
+			KD:           kd(p.Kills, p.Deaths),
+			HeadshotRate: countHeadshotRate(p.Kills, p.Headshots),
+		}
+	}
+
+	return entries, nil
+}
+
+func percent(n, total int) int {
+	if total <= 0 {
+		return 0
+	}
+
+	return int(math.Round(float64(n) / float64(total) * 100))
+}
+
+// kd is kills per death to two decimals, or the kills of a player who has
+// not died.
+func kd(kills, deaths int) float64 {
+	if deaths == 0 {
+		return float64(kills)
+	}
+
+	return math.Round(float64(kills)/float64(deaths)*100) / 100
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	m "github.com/cs2-server/backend/internal/model"
+)
+
+type fakeMapStorage struct {
+	mapStorage
+	maps    []m.MapStats
+	players []m.MapPlayerStats
+	limit   int
+}
+
+func (s *fakeMapStorage) RecordRound(_ context.Context, serverID int64, input m.RoundInput) (m.Round, error) {
+	return m.Round{ID: 1, ServerID: serverID, Map: input.Map, Winner: input.Winner, Duration: input.Duration}, nil
+}
+
+func (s *fakeMapStorage) ListMaps(context.Context) ([]m.MapStats, error) {
+	return s.maps, nil
+}
+
+func (s *fakeMapStorage) GetLeaderboard(_ context.Context, _ string, limit int) ([]m.MapPlayerStats, error) {
+	s.limit = limit
+
+	return s.players, nil
+}
+
+func TestMapServiceListMaps(t *testing.T) {
+	storage := &fakeMapStorage{maps: []m.MapStats{
+		{Name: "de_dust2", Rounds: 3, CTWins: 2, TWins: 1, AvgRoundLength: 95.04},
+	}}
+
+	maps, err := NewMapService(storage, &fakePublisher{}).ListMaps(context.Background())
+	if err != nil {
+		t.Fatalf("ListMaps: %v", err)
+	}
+
+	got := maps[0]
+	if got.CTWinRate != 67 || got.TWinRate != 33 || got.AvgRoundLength != 95 {
+		t.Fatalf("got %+v", got)
+	}
+}
+
+func TestMapServiceLeaderboard(t *testing.T) {
+	storage := &fakeMapStorage{players: []m.MapPlayerStats{
+		{SteamID: "1", Rounds: 10, Kills: 20, Deaths: 0, Headshots: 10},
+		{SteamID: "2", Rounds: 10, Kills: 10, Deaths: 3, Headshots: 1},
+	}}
+	s := NewMapService(storage, &fakePublisher{})
+
+	entries, err := s.Leaderboard(context.Background(), "de_dust2", 1000)
+	if err != nil {
+		t.Fatalf("Leaderboard: %v", err)
+	}
+
+	if storage.limit != maxLeaderboardLimit {
+		t.Errorf("limit %d, want %d", storage.limit, maxLeaderboardLimit)
+	}
+
+	want := []m.LeaderboardEntry{
+		{Rank: 1, SteamID: "1", Rounds: 10, Kills: 20, KD: 20, HeadshotRate: 50},
+		{Rank: 2, SteamID: "2", Rounds: 10, Kills: 10, Deaths: 3, KD: 3.33, HeadshotRate: 10},
+	}
+	for i := range want {
+		if entries[i] != want[i] {
+			t.Errorf("entry %d: got %+v, want %+v", i, entries[i], want[i])
+		}
+	}
+
+	storage.players = nil
+	if _, err := s.Leaderboard(context.Background(), "de_unknown", 0); !errors.Is(err, m.ErrNotFound) {
+		t.Fatalf("unplayed map: got %v, want ErrNotFound", err)
+	}
+
+	if storage.limit != defaultLeaderboardLimit {
+		t.Errorf("limit %d, want %d", storage.limit, defaultLeaderboardLimit)
+	}
+}
+
+func TestMapServiceRecordRound(t *testing.T) {
+	events := &fakePublisher{}
+	s := NewMapService(&fakeMapStorage{}, events)
+
+	invalid := m.RoundInput{Map: "de_dust2", Winner: "spectator", Duration: 90}
+	if _, err := s.RecordRound(context.Background(), 1, invalid); !errors.Is(err, m.ErrInvalidInput) {
+		t.Fatalf("invalid round: got %v, want ErrInvalidInput", err)
+	}
+
+	input := m.RoundInput{
+		Map:      "de_dust2",
+		Winner:   m.SideCT,
+		Duration: 90,
+		Players:  []m.RoundPlayer{{SteamID: "1", Side: m.SideCT, Kills: 2, Headshots: 1}},
+	}
+	if _, err := s.RecordRound(context.Background(), 1, input); err != nil {
+		t.Fatalf("RecordRound: %v", err)
+	}
+
+	if len(events.events) != 1 || events.events[0].Type != m.EventRoundEnd {
+		t.Fatalf("got events %+v, want one round_end", events.events)
+	}
+}
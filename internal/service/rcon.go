@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/pkg/rcon"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultRCONLogLimit = 50
+	maxRCONLogLimit     = 500
+)
+
+type serverGetter interface {
+	GetServer(context.Context, int64) (m.Server, error)
+}
+
+type rconLogStorage interface {
+	CreateRCONLog(context.Context, m.RCONLog) error
+	ListRCONLogs(context.Context, int64, int) ([]m.RCONLog, error)
+}
+
+type rconConn struct {
+	addr     string
+	password string
+	client   *rcon.Client
+}
+
+type RCONService struct {
+	servers serverGetter
+	roles   adminStorage
+	logs    rconLogStorage
+	timeout time.Duration
+	logger  *logrus.Logger
+
+	mu    sync.Mutex
+	conns map[int64]*rconConn
+}
+
+func NewRCONService(servers serverGetter, roles adminStorage, logs rconLogStorage, timeout time.Duration, logger *logrus.Logger) *RCONService {
+	return &RCONService{
+		servers: servers,
+		roles:   roles,
+		logs:    logs,
+		timeout: timeout,
+		logger:  logger,
+		conns:   make(map[int64]*rconConn),
+	}
+}
+
+// Execute runs command on the server on behalf of the admin, if the admin's
+// role allows it. Every attempt is written to the audit log, denied ones
+// included.
+func (s *RCONService) Execute(ctx context.Context, steamID string, serverID int64, command string) (string, error) {
+	role, err := s.roles.GetRole(ctx, steamID)
+	if err != nil {
+		if errors.Is(err, m.ErrNotFound) {
+			return "", fmt.Errorf("Execute (1): %w", m.ErrForbidden)
+		}
+
+		return "", fmt.Errorf("Execute (1): %w", err)
+	}
+
+	srv, err := s.servers.GetServer(ctx, serverID)
+	if err != nil {
+		return "", fmt.Errorf("Execute (2): %w", err)
+	}
+
+	if !role.CanRun(command) {
+		err := fmt.Errorf("%w: command is not allowed for %s", m.ErrForbidden, role)
+		s.audit(ctx, serverID, steamID, command, "", err)
+
+		return "", fmt.Errorf("Execute (3): %w", err)
+	}
+
+	if srv.RCONPassword == "" {
+		err := fmt.Errorf("%w: rcon is not configured for the server", m.ErrInvalidInput)
+		s.audit(ctx, serverID, steamID, command, "", err)
+
+		return "", fmt.Errorf("Execute (4): %w", err)
+	}
+
+	out, err := s.client(srv).Execute(ctx, command)
+	s.audit(ctx, serverID, steamID, command, out, err)
+
+	if err != nil {
+		return "", fmt.Errorf("Execute (5): %w", err)
+	}
+
+	return out, nil
+}
+
+func (s *RCONService) ListLogs(ctx context.Context, serverID int64, limit int) ([]m.RCONLog, error) {
+	if limit <= 0 {
+		limit = defaultRCONLogLimit
+	}
+
+	if limit > maxRCONLogLimit {
+		limit = maxRCONLogLimit
+	}
+
+	logs, err := s.logs.ListRCONLogs(ctx, serverID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ListLogs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// Close drops every open RCON connection.
+func (s *RCONService) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, conn := range s.conns {
+		conn.client.Close()
+		delete(s.conns, id)
+	}
+}
+
+// client returns a cached client for the server, replacing it when the
+// address or password has changed since it was created.
+func (s *RCONService) client(srv m.Server) *rcon.Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	conn, ok := s.conns[srv.ID]
+	if ok && conn.addr == srv.Addr() && conn.password == srv.RCONPassword {
+		return conn.client
+	}
+
+	if ok {
+		conn.client.Close()
+	}
+
+	conn = &rconConn{
+		addr:     srv.Addr(),
+		password: srv.RCONPassword,
+		client:   rcon.NewClient(srv.Addr(), srv.RCONPassword, s.timeout),
+	}
+	s.conns[srv.ID] = conn
+
+	return conn.client
+}
+
+func (s *RCONService) audit(ctx context.Context, serverID int64, steamID, command, output string, cmdErr error) {
+	log := m.RCONLog{
+		ServerID: serverID,
+		SteamID:  steamID,
+		Command:  command,
+		Output:   output,
+	}
+
+	if cmdErr != nil {
+		log.Error = cmdErr.Error()
+	}
+
+	// The audit entry is written even if the request was cancelled.
+	if err := s.logs.CreateRCONLog(context.WithoutCancel(ctx), log); err != nil {
+		s.logger.WithContext(ctx).Errorln(err)
+	}
+
+	s.logger.WithContext(ctx).WithFields(logrus.Fields{
+		"server_id": serverID,
+		"steam_id":  steamID,
+		"command":   command,
+		"error":     log.Error,
+	}).Info("rcon command")
+}
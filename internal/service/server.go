@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/pkg/a2s"
+	"github.com/sirupsen/logrus"
+)
+
+type serverStorage interface {
+	ListServers(context.Context) ([]m.Server, error)
+	GetServer(context.Context, int64) (m.Server, error)
+	CreateServer(context.Context, m.ServerInput) (m.Server, error)
+	UpdateServer(context.Context, int64, m.ServerInput) (m.Server, error)
+	DeleteServer(context.Context, int64) error
+}
+
+type serverQuerier interface {
+	Info(context.Context, string) (a2s.Info, error)
+	Players(context.Context, string) ([]a2s.Player, error)
+}
+
+type eventPublisher interface {
+	Publish(m.Event)
+}
+
+type ServerService struct {
+	storage serverStorage
+	querier serverQuerier
+	events  eventPublisher
+	logger  *logrus.Logger
+
+	mu       sync.RWMutex
+	statuses map[int64]m.ServerStatus
+}
+
+func NewServerService(storage serverStorage, querier serverQuerier, events eventPublisher, logger *logrus.Logger) *ServerService {
+	return &ServerService{
+		storage:  storage,
+		querier:  querier,
+		events:   events,
+		logger:   logger,
+		statuses: make(map[int64]m.ServerStatus),
+	}
+}
+
+func (s *ServerService) ListServers(ctx context.Context) ([]m.ServerInfo, error) {
+	servers, err := s.storage.ListServers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("ListServers: %w", err)
+	}
+
+	infos := make([]m.ServerInfo, 0, len(servers))
+	for _, srv := range servers {
+		infos = append(infos, m.ServerInfo{
+			Server: srv,
+			Status: s.status(srv.ID),
+		})
+	}
+
+	return infos, nil
+}
+
+func (s *ServerService) GetServer(ctx context.Context, ID int64) (m.ServerInfo, error) {
+	srv, err := s.storage.GetServer(ctx, ID)
+	if err != nil {
+		return m.ServerInfo{}, fmt.Errorf("GetServer: %w", err)
+	}
+
+	return m.ServerInfo{
+		Server: srv,
+		Status: s.status(srv.ID),
+	}, nil
+}
+
+func (s *ServerService) CreateServer(ctx context.Context, input m.ServerInput) (m.Server, error) {
+	if err := input.Validate(); err != nil {
+		return m.Server{}, fmt.Errorf("CreateServer (1): %w", err)
+	}
+
+	srv, err := s.storage.CreateServer(ctx, input)
+	if err != nil {
+		return m.Server{}, fmt.Errorf("CreateServer (2): %w", err)
+	}
+
+	return srv, nil
+}
+
+func (s *ServerService) UpdateServer(ctx context.Context, ID int64, input m.ServerInput) (m.Server, error) {
+	if err := input.Validate(); err != nil {
+		return m.Server{}, fmt.Errorf("UpdateServer (1): %w", err)
+	}
+
+	srv, err := s.storage.UpdateServer(ctx, ID, input)
+	if err != nil {
+		return m.Server{}, fmt.Errorf("UpdateServer (2): %w", err)
+	}
+
+	s.mu.Lock()
+	delete(s.statuses, ID)
+	s.mu.Unlock()
+
+	return srv, nil
+}
+
+func (s *ServerService) DeleteServer(ctx context.Context, ID int64) error {
+	if err := s.storage.DeleteServer(ctx, ID); err != nil {
+		return fmt.Errorf("DeleteServer: %w", err)
+	}
+
+	s.mu.Lock()
+	delete(s.statuses, ID)
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Run polls every registered server once per interval until ctx is done.
+func (s *ServerService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Poll(ctx); err != nil {
+			s.logger.Errorln(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll queries A2S_INFO and A2S_PLAYER from every registered server
+// concurrently and replaces the cached statuses. Servers whose status changed
+// are announced on the servers topic.
+func (s *ServerService) Poll(ctx context.Context) error {
+	servers, err := s.storage.ListServers(ctx)
+	if err != nil {
+		return fmt.Errorf("Poll: %w", err)
+	}
+
+	statuses := make(map[int64]m.ServerStatus, len(servers))
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	for _, srv := range servers {
+		wg.Add(1)
+
+		go func(srv m.Server) {
+			defer wg.Done()
+
+			status := s.query(ctx, srv)
+
+			mu.Lock()
+			statuses[srv.ID] = status
+			mu.Unlock()
+		}(srv)
+	}
+
+	wg.Wait()
+
+	s.mu.Lock()
+	previous := s.statuses
+	s.statuses = statuses
+	s.mu.Unlock()
+
+	for id, status := range statuses {
+		if !statusChanged(previous[id], status) {
+			continue
+		}
+
+		s.events.Publish(m.Event{
+			Topic: m.TopicServers,
+			Type:  m.EventServerStatus,
+			Data:  m.ServerStatusEvent{ServerID: id, Status: status},
+		})
+	}
+
+	return nil
+}
+
+func statusChanged(old, new m.ServerStatus) bool {
+	return old.CheckedAt == nil ||
+		old.Online != new.Online ||
+		old.Map != new.Map ||
+		old.Players != new.Players ||
+		old.MaxPlayers != new.MaxPlayers
+}
+
+func (s *ServerService) query(ctx context.Context, srv m.Server) m.ServerStatus {
+	now := time.Now()
+	status := m.ServerStatus{
+		PlayerList: []m.ServerPlayer{},
+		CheckedAt:  &now,
+	}
+
+	info, err := s.querier.Info(ctx, srv.Addr())
+	if err != nil {
+		s.logger.Warnf("server %d (%s) is offline: %v", srv.ID, srv.Addr(), err)
+
+		return status
+	}
+
+	status.Online = true
+	status.Map = info.Map
+	status.Players = info.Players
+	status.MaxPlayers = info.MaxPlayers
+	status.Bots = info.Bots
+
+	players, err := s.querier.Players(ctx, srv.Addr())
+	if err != nil {
+		s.logger.Warnf("server %d (%s) players: %v", srv.ID, srv.Addr(), err)
+
+		return status
+	}
+
+	for _, p := range players {
+		status.PlayerList = append(status.PlayerList, m.ServerPlayer{
+			Name:     p.Name,
+			Score:    int(p.Score),
+			Duration: float64(p.Duration),
+		})
+	}
+
+	return status
+}
+
+func (s *ServerService) status(ID int64) m.ServerStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	status, ok := s.statuses[ID]
+	if !ok {
+		return m.ServerStatus{PlayerList: []m.ServerPlayer{}}
+	}
+
+	return status
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/pkg/a2s"
+	"github.com/cs2-server/backend/pkg/a2s/a2stest"
+	"github.com/sirupsen/logrus"
+)
+
+type fakeServerStorage struct {
+	serverStorage
+	servers []m.Server
+}
+
+func (s *fakeServerStorage) ListServers(context.Context) ([]m.Server, error) {
+	return s.servers, nil
+}
+
+type fakePublisher struct {
+	events []m.Event
+}
+
+func (p *fakePublisher) Publish(ev m.Event) {
+	p.events = append(p.events, ev)
+}
+
+func TestServerServicePoll(t *testing.T) {
+	stub, err := a2stest.NewServer(
+		a2s.Info{Map: "de_overpass", Players: 1, MaxPlayers: 10},
+		[]a2s.Player{{Name: "player", Score: 5, Duration: 30}},
+		true,
+	)
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	defer stub.Close()
+
+	online := serverFromAddr(t, 1, stub.Addr())
+	offline := m.Server{ID: 2, Host: "127.0.0.1", Port: 1}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	events := &fakePublisher{}
+	s := NewServerService(&fakeServerStorage{servers: []m.Server{online, offline}}, a2s.NewClient(200*time.Millisecond), events, logger)
+
+	if err := s.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+
+	if len(events.events) != 2 {
+		t.Errorf("got %d status events after first poll, want 2", len(events.events))
+	}
+
+	if err := s.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll: %v", err)
+	}
+
+	if len(events.events) != 2 {
+		t.Errorf("got %d status events after unchanged poll, want 2", len(events.events))
+	}
+
+	infos, err := s.ListServers(context.Background())
+	if err != nil {
+		t.Fatalf("ListServers: %v", err)
+	}
+
+	if len(infos) != 2 {
+		t.Fatalf("got %d servers, want 2", len(infos))
+	}
+
+	got := infos[0].Status
+	if !got.Online || got.Map != "de_overpass" || got.Players != 1 || len(got.PlayerList) != 1 || got.PlayerList[0].Name != "player" {
+		t.Errorf("unexpected online status: %+v", got)
+	}
+
+	if infos[1].Status.Online {
+		t.Errorf("unexpected offline status: %+v", infos[1].Status)
+	}
+}
+
+func serverFromAddr(t *testing.T, id int64, addr string) m.Server {
+	t.Helper()
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		t.Fatalf("SplitHostPort: %v", err)
+	}
+
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		t.Fatalf("Atoi: %v", err)
+	}
+
+	return m.Server{ID: id, Host: host, Port: p}
+}
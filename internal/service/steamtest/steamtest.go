@@ -0,0 +1,49 @@
+// Package steamtest stubs the parts of the Steam Web API the service uses,
+// for use in tests.
+package steamtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	m "github.com/cs2-server/backend/internal/model"
+)
+
+// Key is the only API key the stub accepts.
+const Key = "steam-test-key"
+
+// NewServer answers GetPlayerSummaries with Player(id) for every requested
+// SteamID. It is closed when the test ends.
+func NewServer(t testing.TB) *httptest.Server {
+	t.Helper()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ISteamUser/GetPlayerSummaries/v0002/" || r.URL.Query().Get("key") != Key {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		var res m.PlayerResponse
+		for _, id := range strings.Split(r.URL.Query().Get("steamids"), ",") {
+			res.Response.Players = append(res.Response.Players, Player(id))
+		}
+
+		json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv
+}
+
+// Player is the summary the stub returns for id.
+func Player(id string) m.Player {
+	return m.Player{
+		ID:     id,
+		Name:   "player " + id,
+		URL:    "https://steamcommunity.com/profiles/" + id,
+		Avatar: "https://avatars.example.com/" + id + ".jpg",
+	}
+}
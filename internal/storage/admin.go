@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type AdminStorage struct {
+	db *pgxpool.Pool
+}
+
+func NewAdminStorage(db *pgxpool.Pool) *AdminStorage {
+	return &AdminStorage{
+		db: db,
+	}
+}
+
+func (s *AdminStorage) GetRole(ctx context.Context, steamID string) (m.Role, error) {
+	query := `
+        SELECT role
+        FROM admins
+        WHERE steam_id = $1
+    `
+
+	var role m.Role
+	if err := s.db.QueryRow(ctx, query, steamID).Scan(&role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", fmt.Errorf("GetRole: %w", m.ErrNotFound)
+		}
+
+		return "", fmt.Errorf("GetRole: %w", err)
+	}
+
+	return role, nil
+}
+
+// SetRole makes steamID an admin with role, replacing any role it had.
+func (s *AdminStorage) SetRole(ctx context.Context, steamID string, role m.Role) error {
+	query := `
+        INSERT INTO admins (steam_id, role)
+        VALUES ($1, $2)
+        ON CONFLICT (steam_id) DO UPDATE SET role = EXCLUDED.role
+    `
+
+	if _, err := s.db.Exec(ctx, query, steamID, role); err != nil {
+		return fmt.Errorf("SetRole: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	apiKeyColumns = "id, server_id, name, prefix, scopes, created_at, last_used_at, last_used_ip, revoked_at"
+
+	// apiKeyTouchInterval limits how often last use of a key is written.
+	apiKeyTouchInterval = "1 minute"
+)
+
+type APIKeyStorage struct {
+	db *pgxpool.Pool
+}
+
+func NewAPIKeyStorage(db *pgxpool.Pool) *APIKeyStorage {
+	return &APIKeyStorage{
+		db: db,
+	}
+}
+
+func (s *APIKeyStorage) CreateAPIKey(ctx context.Context, serverID int64, input m.APIKeyInput, prefix, hash string) (m.APIKey, error) {
+	query := `
+        INSERT INTO api_keys (server_id, name, scopes, prefix, key_hash)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(s.db.QueryRow(ctx, query, serverID, input.Name, scopesToStrings(input.Scopes), prefix, hash))
+	if err != nil {
+		return m.APIKey{}, fmt.Errorf("CreateAPIKey: %w", err)
+	}
+
+	return key, nil
+}
+
+// RotateAPIKey revokes the key and creates a new one with the same name and
+// scopes in a single transaction.
+func (s *APIKeyStorage) RotateAPIKey(ctx context.Context, serverID, ID int64, prefix, hash string) (m.APIKey, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return m.APIKey{}, fmt.Errorf("RotateAPIKey (1): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	revoke := `
+        UPDATE api_keys
+        SET revoked_at = now()
+        WHERE id = $1 AND server_id = $2 AND revoked_at IS NULL
+        RETURNING ` + apiKeyColumns
+
+	old, err := scanAPIKey(tx.QueryRow(ctx, revoke, ID, serverID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return m.APIKey{}, fmt.Errorf("RotateAPIKey (2): %w", m.ErrNotFound)
+		}
+
+		return m.APIKey{}, fmt.Errorf("RotateAPIKey (2): %w", err)
+	}
+
+	create := `
+        INSERT INTO api_keys (server_id, name, scopes, prefix, key_hash)
+        VALUES ($1, $2, $3, $4, $5)
+        RETURNING ` + apiKeyColumns
+
+	key, err := scanAPIKey(tx.QueryRow(ctx, create, serverID, old.Name, scopesToStrings(old.Scopes), prefix, hash))
+	if err != nil {
+		return m.APIKey{}, fmt.Errorf("RotateAPIKey (3): %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return m.APIKey{}, fmt.Errorf("RotateAPIKey (4): %w", err)
+	}
+
+	return key, nil
+}
+
+func (s *APIKeyStorage) RevokeAPIKey(ctx context.Context, serverID, ID int64) error {
+	query := `
+        UPDATE api_keys
+        SET revoked_at = now()
+        WHERE id = $1 AND server_id = $2 AND revoked_at IS NULL
+    `
+
+	tag, err := s.db.Exec(ctx, query, ID, serverID)
+	if err != nil {
+		return fmt.Errorf("RevokeAPIKey: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("RevokeAPIKey: %w", m.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (s *APIKeyStorage) ListAPIKeys(ctx context.Context, serverID int64) ([]m.APIKey, error) {
+	query := `
+        SELECT ` + apiKeyColumns + `
+        FROM api_keys
+        WHERE server_id = $1
+        ORDER BY id
+    `
+
+	rows, err := s.db.Query(ctx, query, serverID)
+	if err != nil {
+		return nil, fmt.Errorf("ListAPIKeys (1): %w", err)
+	}
+	defer rows.Close()
+
+	keys := []m.APIKey{}
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListAPIKeys (2): %w", err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListAPIKeys (3): %w", err)
+	}
+
+	return keys, nil
+}
+
+// TouchAPIKey looks up an active key by hash and records its use. The row is
+// written at most once per apiKeyTouchInterval unless the client IP changes.
+func (s *APIKeyStorage) TouchAPIKey(ctx context.Context, hash, ip string) (m.APIKey, error) {
+	query := `
+        WITH key AS (
+            SELECT ` + apiKeyColumns + `
+            FROM api_keys
+            WHERE key_hash = $1 AND revoked_at IS NULL
+        ), touched AS (
+            UPDATE api_keys
+            SET last_used_at = now(), last_used_ip = $2
+            WHERE id = (SELECT id FROM key)
+                AND (last_used_at IS NULL OR last_used_at < now() - interval '` + apiKeyTouchInterval + `' OR last_used_ip <> $2)
+        )
+        SELECT ` + apiKeyColumns + `
+        FROM key
+    `
+
+	key, err := scanAPIKey(s.db.QueryRow(ctx, query, hash, ip))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return m.APIKey{}, fmt.Errorf("TouchAPIKey: %w", m.ErrNotFound)
+		}
+
+		return m.APIKey{}, fmt.Errorf("TouchAPIKey: %w", err)
+	}
+
+	return key, nil
+}
+
+func scanAPIKey(row pgx.Row) (m.APIKey, error) {
+	var (
+		key    m.APIKey
+		scopes []string
+	)
+
+	if err := row.Scan(&key.ID, &key.ServerID, &key.Name, &key.Prefix, &scopes, &key.CreatedAt, &key.LastUsedAt, &key.LastUsedIP, &key.RevokedAt); err != nil {
+		return m.APIKey{}, err
+	}
+
+	key.Scopes = make([]m.APIKeyScope, 0, len(scopes))
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, m.APIKeyScope(scope))
+	}
+
+	return key, nil
+}
+
+func scopesToStrings(scopes []m.APIKeyScope) []string {
+	s := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		s = append(s, string(scope))
+	}
+
+	return s
+}
@@ -2,19 +2,29 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/cs2-server/backend/internal/breaker"
 	m "github.com/cs2-server/backend/internal/model"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+// AuthStorage bounds each query by timeout and stops querying through breaker
+// while the database keeps failing, returning m.ErrUnavailable instead.
 type AuthStorage struct {
-	db *pgxpool.Pool
+	db      *pgxpool.Pool
+	timeout time.Duration
+	breaker *breaker.Breaker
 }
 
-func NewAuthStorage(db *pgxpool.Pool) *AuthStorage {
+func NewAuthStorage(db *pgxpool.Pool, timeout time.Duration, breaker *breaker.Breaker) *AuthStorage {
 	return &AuthStorage{
-		db: db,
+		db:      db,
+		timeout: timeout,
+		breaker: breaker,
 	}
 }
 
@@ -25,9 +35,37 @@ func (s *AuthStorage) GetProfileStatsByID(ctx context.Context, ID string) (m.Sta
         WHERE steam_id = $1
     `
 
+	if err := s.breaker.Allow(); err != nil {
+		return m.Stats{}, fmt.Errorf("GetProfileStats (1): %w: %w", m.ErrUnavailable, err)
+	}
+
+	ctx, span := startSpan(ctx, "AuthStorage.GetProfileStatsByID", "SELECT", "player_stats")
+	defer span.End()
+
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
 	var stats m.Stats
-	if err := s.db.QueryRow(ctx, query, ID).Scan(&stats.Kills, &stats.Deaths, &stats.Headshots); err != nil {
-		return m.Stats{}, fmt.Errorf("GetProfileStats: %w", err)
+	err := s.db.QueryRow(ctx, query, ID).Scan(&stats.Kills, &stats.Deaths, &stats.Headshots)
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// The caller gave up, which says nothing about the database.
+		s.breaker.Release()
+	case errors.Is(err, pgx.ErrNoRows):
+		s.breaker.Report(nil)
+	default:
+		s.breaker.Report(err)
+	}
+
+	if err != nil {
+		recordError(span, err)
+
+		if errors.Is(err, context.DeadlineExceeded) {
+			return m.Stats{}, fmt.Errorf("GetProfileStats (2): %w: %w", m.ErrUnavailable, err)
+		}
+
+		return m.Stats{}, fmt.Errorf("GetProfileStats (3): %w", err)
 	}
 
 	return stats, nil
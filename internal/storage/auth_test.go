@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cs2-server/backend/internal/breaker"
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/storage/storagetest"
+	"github.com/jackc/pgx/v4"
+)
+
+func TestAuthStorage(t *testing.T) {
+	db := storagetest.New(t)
+	storagetest.Load(t, db)
+
+	ctx := context.Background()
+	b := breaker.New(1, time.Minute)
+	s := NewAuthStorage(db, time.Second, b)
+
+	stats, err := s.GetProfileStatsByID(ctx, storagetest.PlayerID)
+	if err != nil {
+		t.Fatalf("GetProfileStatsByID: %v", err)
+	}
+
+	if want := (m.Stats{Kills: 120, Deaths: 80, Headshots: 48}); stats != want {
+		t.Fatalf("got %+v, want %+v", stats, want)
+	}
+
+	if _, err := s.GetProfileStatsByID(ctx, storagetest.UnknownID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Fatalf("unknown player: got %v, want ErrNoRows", err)
+	}
+
+	if b.Open() {
+		t.Fatal("a missing row must not open the breaker")
+	}
+
+	slow := NewAuthStorage(db, time.Nanosecond, b)
+	if _, err := slow.GetProfileStatsByID(ctx, storagetest.PlayerID); !errors.Is(err, m.ErrUnavailable) {
+		t.Fatalf("timed out query: got %v, want ErrUnavailable", err)
+	}
+
+	if _, err := s.GetProfileStatsByID(ctx, storagetest.PlayerID); !errors.Is(err, breaker.ErrOpen) {
+		t.Fatalf("after a timeout: got %v, want ErrOpen", err)
+	}
+}
@@ -0,0 +1,170 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	banColumns = `id, type, steam_id, ip, reason, admin_id, duration, created_at, expires_at,
+        lifted_at, lifted_by, lift_reason,
+        lifted_at IS NULL AND (expires_at IS NULL OR expires_at > now()) AS active`
+
+	banActive = "lifted_at IS NULL AND (expires_at IS NULL OR expires_at > now())"
+)
+
+type BanStorage struct {
+	db *pgxpool.Pool
+}
+
+func NewBanStorage(db *pgxpool.Pool) *BanStorage {
+	return &BanStorage{
+		db: db,
+	}
+}
+
+func (s *BanStorage) CreateBan(ctx context.Context, adminID string, input m.BanInput) (m.Ban, error) {
+	query := `
+        INSERT INTO bans (type, steam_id, ip, reason, admin_id, duration, expires_at)
+        VALUES ($1, $2, $3, $4, $5, $6::bigint, CASE WHEN $6::bigint > 0 THEN now() + $6::bigint * interval '1 second' END)
+        RETURNING ` + banColumns
+
+	ban, err := scanBan(s.db.QueryRow(ctx, query, input.Type, input.SteamID, input.IP, input.Reason, adminID, input.Duration))
+	if err != nil {
+		return m.Ban{}, fmt.Errorf("CreateBan: %w", err)
+	}
+
+	return ban, nil
+}
+
+func (s *BanStorage) GetBan(ctx context.Context, ID int64) (m.Ban, error) {
+	query := `
+        SELECT ` + banColumns + `
+        FROM bans
+        WHERE id = $1
+    `
+
+	ban, err := scanBan(s.db.QueryRow(ctx, query, ID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return m.Ban{}, fmt.Errorf("GetBan: %w", m.ErrNotFound)
+		}
+
+		return m.Ban{}, fmt.Errorf("GetBan: %w", err)
+	}
+
+	return ban, nil
+}
+
+func (s *BanStorage) ListBans(ctx context.Context, filter m.BanFilter) ([]m.Ban, error) {
+	var (
+		where []string
+		args  []any
+	)
+
+	if filter.SteamID != "" {
+		args = append(args, filter.SteamID)
+		where = append(where, fmt.Sprintf("steam_id = $%d", len(args)))
+	}
+
+	if filter.Type != "" {
+		args = append(args, filter.Type)
+		where = append(where, fmt.Sprintf("type = $%d", len(args)))
+	}
+
+	if filter.Active != nil {
+		if *filter.Active {
+			where = append(where, banActive)
+		} else {
+			where = append(where, "NOT ("+banActive+")")
+		}
+	}
+
+	query := `
+        SELECT ` + banColumns + `
+        FROM bans
+    `
+
+	if len(where) > 0 {
+		query += "WHERE " + strings.Join(where, " AND ")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	return s.queryBans(ctx, "ListBans", query, args...)
+}
+
+// FindActiveBans returns punishments in force for the SteamID or, if set,
+// the IP address.
+func (s *BanStorage) FindActiveBans(ctx context.Context, steamID, ip string) ([]m.Ban, error) {
+	query := `
+        SELECT ` + banColumns + `
+        FROM bans
+        WHERE (steam_id = $1 OR ($2 <> '' AND ip = $2)) AND ` + banActive + `
+        ORDER BY created_at DESC, id DESC
+    `
+
+	return s.queryBans(ctx, "FindActiveBans", query, steamID, ip)
+}
+
+func (s *BanStorage) LiftBan(ctx context.Context, ID int64, adminID, reason string) (m.Ban, error) {
+	query := `
+        UPDATE bans
+        SET lifted_at = now(), lifted_by = $2, lift_reason = $3
+        WHERE id = $1 AND lifted_at IS NULL
+        RETURNING ` + banColumns
+
+	ban, err := scanBan(s.db.QueryRow(ctx, query, ID, adminID, reason))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return m.Ban{}, fmt.Errorf("LiftBan: %w", m.ErrNotFound)
+		}
+
+		return m.Ban{}, fmt.Errorf("LiftBan: %w", err)
+	}
+
+	return ban, nil
+}
+
+func (s *BanStorage) queryBans(ctx context.Context, op, query string, args ...any) ([]m.Ban, error) {
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s (1): %w", op, err)
+	}
+	defer rows.Close()
+
+	bans := []m.Ban{}
+	for rows.Next() {
+		ban, err := scanBan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s (2): %w", op, err)
+		}
+
+		bans = append(bans, ban)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s (3): %w", op, err)
+	}
+
+	return bans, nil
+}
+
+func scanBan(row pgx.Row) (m.Ban, error) {
+	var ban m.Ban
+	if err := row.Scan(
+		&ban.ID, &ban.Type, &ban.SteamID, &ban.IP, &ban.Reason, &ban.AdminID, &ban.Duration, &ban.CreatedAt, &ban.ExpiresAt,
+		&ban.LiftedAt, &ban.LiftedBy, &ban.LiftReason, &ban.Active,
+	); err != nil {
+		return m.Ban{}, err
+	}
+
+	return ban, nil
+}
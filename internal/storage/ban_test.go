@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/storage/storagetest"
+)
+
+func TestBanStorageActive(t *testing.T) {
+	db := storagetest.New(t)
+	storagetest.Load(t, db)
+
+	ctx := context.Background()
+	s := NewBanStorage(db)
+
+	ban := func(input m.BanInput) m.Ban {
+		t.Helper()
+
+		b, err := s.CreateBan(ctx, storagetest.AdminID, input)
+		if err != nil {
+			t.Fatalf("CreateBan: %v", err)
+		}
+
+		return b
+	}
+
+	permanent := ban(m.BanInput{Type: m.BanTypeBan, SteamID: storagetest.PlayerID, Reason: "cheating"})
+	timed := ban(m.BanInput{Type: m.BanTypeMute, SteamID: storagetest.UnknownID, IP: "203.0.113.7", Reason: "spam", Duration: 3600})
+	expired := ban(m.BanInput{Type: m.BanTypeGag, SteamID: storagetest.PlayerID, Reason: "spam", Duration: 60})
+
+	if _, err := db.Exec(ctx, "UPDATE bans SET expires_at = now() - interval '1 minute' WHERE id = $1", expired.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if !permanent.Active || permanent.ExpiresAt != nil || !timed.Active || timed.ExpiresAt == nil {
+		t.Fatalf("created %+v %+v", permanent, timed)
+	}
+
+	// The timed mute matches by IP although the SteamID differs.
+	active, err := s.FindActiveBans(ctx, storagetest.PlayerID, "203.0.113.7")
+	if err != nil {
+		t.Fatalf("FindActiveBans: %v", err)
+	}
+
+	if len(active) != 2 || active[0].ID != timed.ID || active[1].ID != permanent.ID {
+		t.Fatalf("active bans %+v", active)
+	}
+
+	lifted, err := s.LiftBan(ctx, permanent.ID, storagetest.AdminID, "appeal")
+	if err != nil {
+		t.Fatalf("LiftBan: %v", err)
+	}
+
+	if lifted.Active || lifted.LiftedAt == nil || lifted.LiftReason != "appeal" {
+		t.Errorf("lifted %+v", lifted)
+	}
+
+	if _, err := s.LiftBan(ctx, permanent.ID, storagetest.AdminID, "again"); !errors.Is(err, m.ErrNotFound) {
+		t.Errorf("lifting twice: got %v", err)
+	}
+
+	active, err = s.FindActiveBans(ctx, storagetest.PlayerID, "")
+	if err != nil {
+		t.Fatalf("FindActiveBans: %v", err)
+	}
+
+	if len(active) != 0 {
+		t.Errorf("expired or lifted bans still active: %+v", active)
+	}
+
+	for _, tt := range []struct {
+		active bool
+		want   int
+	}{
+		{active: true, want: 0},
+		{active: false, want: 2},
+	} {
+		bans, err := s.ListBans(ctx, m.BanFilter{SteamID: storagetest.PlayerID, Active: &tt.active, Limit: 10})
+		if err != nil {
+			t.Fatalf("ListBans: %v", err)
+		}
+
+		if len(bans) != tt.want {
+			t.Errorf("active=%v: got %d bans, want %d", tt.active, len(bans), tt.want)
+		}
+	}
+
+	if _, err := s.GetBan(ctx, 1<<40); !errors.Is(err, m.ErrNotFound) {
+		t.Errorf("GetBan of a missing ban: got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+var ErrNoMigrations = errors.New("migrations have not been run")
+
+type HealthStorage struct {
+	db *pgxpool.Pool
+}
+
+func NewHealthStorage(db *pgxpool.Pool) *HealthStorage {
+	return &HealthStorage{
+		db: db,
+	}
+}
+
+func (s *HealthStorage) Ping(ctx context.Context) error {
+	if err := s.db.Ping(ctx); err != nil {
+		return fmt.Errorf("Ping: %w", err)
+	}
+
+	return nil
+}
+
+// MigrationVersion returns the schema version recorded by golang-migrate and
+// whether the last migration failed halfway.
+func (s *HealthStorage) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	query := `
+        SELECT version, dirty
+        FROM schema_migrations
+        LIMIT 1
+    `
+
+	var (
+		version int64
+		dirty   bool
+	)
+
+	if err := s.db.QueryRow(ctx, query).Scan(&version, &dirty); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, false, fmt.Errorf("MigrationVersion: %w", ErrNoMigrations)
+		}
+
+		return 0, false, fmt.Errorf("MigrationVersion: %w", err)
+	}
+
+	return uint(version), dirty, nil
+}
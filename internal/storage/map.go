@@ -0,0 +1,143 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type MapStorage struct {
+	db *pgxpool.Pool
+}
+
+func NewMapStorage(db *pgxpool.Pool) *MapStorage {
+	return &MapStorage{
+		db: db,
+	}
+}
+
+// RecordRound stores the round and adds what its players did to their
+// totals on the map, in a single transaction.
+func (s *MapStorage) RecordRound(ctx context.Context, serverID int64, input m.RoundInput) (m.Round, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return m.Round{}, fmt.Errorf("RecordRound (1): %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	insert := `
+        INSERT INTO rounds (server_id, map, winner, duration_ms)
+        VALUES ($1, $2, $3, $4)
+        RETURNING id, ended_at
+    `
+
+	round := m.Round{
+		ServerID: serverID,
+		Map:      input.Map,
+		Winner:   input.Winner,
+		Duration: input.Duration,
+	}
+
+	durationMS := time.Duration(input.Duration * float64(time.Second)).Milliseconds()
+
+	if err := tx.QueryRow(ctx, insert, serverID, input.Map, input.Winner, durationMS).Scan(&round.ID, &round.EndedAt); err != nil {
+		return m.Round{}, fmt.Errorf("RecordRound (2): %w", err)
+	}
+
+	upsert := `
+        INSERT INTO map_player_stats (map, steam_id, rounds, kills, deaths, headshots)
+        VALUES ($1, $2, 1, $3, $4, $5)
+        ON CONFLICT (map, steam_id) DO UPDATE
+        SET rounds = map_player_stats.rounds + 1,
+            kills = map_player_stats.kills + EXCLUDED.kills,
+            deaths = map_player_stats.deaths + EXCLUDED.deaths,
+            headshots = map_player_stats.headshots + EXCLUDED.headshots
+    `
+
+	for _, p := range input.Players {
+		if _, err := tx.Exec(ctx, upsert, input.Map, p.SteamID, p.Kills, p.Deaths, p.Headshots); err != nil {
+			return m.Round{}, fmt.Errorf("RecordRound (3): %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return m.Round{}, fmt.Errorf("RecordRound (4): %w", err)
+	}
+
+	return round, nil
+}
+
+// ListMaps aggregates the rounds of every map played, most played first.
+// Win rates are left to the caller.
+func (s *MapStorage) ListMaps(ctx context.Context) ([]m.MapStats, error) {
+	query := `
+        SELECT map,
+               count(*),
+               count(*) FILTER (WHERE winner = 'ct'),
+               count(*) FILTER (WHERE winner = 't'),
+               avg(duration_ms)::float8 / 1000,
+               max(ended_at)
+        FROM rounds
+        GROUP BY map
+        ORDER BY count(*) DESC, map
+    `
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ListMaps (1): %w", err)
+	}
+	defer rows.Close()
+
+	maps := []m.MapStats{}
+	for rows.Next() {
+		var st m.MapStats
+		if err := rows.Scan(&st.Name, &st.Rounds, &st.CTWins, &st.TWins, &st.AvgRoundLength, &st.LastPlayed); err != nil {
+			return nil, fmt.Errorf("ListMaps (2): %w", err)
+		}
+
+		maps = append(maps, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListMaps (3): %w", err)
+	}
+
+	return maps, nil
+}
+
+// GetLeaderboard returns the players with the most kills on the map, fewer
+// deaths breaking ties.
+func (s *MapStorage) GetLeaderboard(ctx context.Context, mapName string, limit int) ([]m.MapPlayerStats, error) {
+	query := `
+        SELECT steam_id, rounds, kills, deaths, headshots
+        FROM map_player_stats
+        WHERE map = $1
+        ORDER BY kills DESC, deaths, steam_id
+        LIMIT $2
+    `
+
+	rows, err := s.db.Query(ctx, query, mapName, limit)
+	if err != nil {
+		return nil, fmt.Errorf("GetLeaderboard (1): %w", err)
+	}
+	defer rows.Close()
+
+	players := []m.MapPlayerStats{}
+	for rows.Next() {
+		var p m.MapPlayerStats
+		if err := rows.Scan(&p.SteamID, &p.Rounds, &p.Kills, &p.Deaths, &p.Headshots); err != nil {
+			return nil, fmt.Errorf("GetLeaderboard (2): %w", err)
+		}
+
+		players = append(players, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("GetLeaderboard (3): %w", err)
+	}
+
+	return players, nil
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/cs2-server/backend/internal/storage/storagetest"
+)
+
+func TestMapStorage(t *testing.T) {
+	db := storagetest.New(t)
+	storagetest.Load(t, db)
+
+	ctx := context.Background()
+	s := NewMapStorage(db)
+
+	input := m.RoundInput{
+		Map:      "de_mirage",
+		Winner:   m.SideT,
+		Duration: 80.5,
+		Players: []m.RoundPlayer{
+			{SteamID: storagetest.PlayerID, Side: m.SideT, Kills: 3, Headshots: 2},
+			{SteamID: storagetest.AdminID, Side: m.SideCT, Deaths: 1},
+		},
+	}
+
+	var serverID int64
+	if err := db.QueryRow(ctx, "SELECT id FROM servers LIMIT 1").Scan(&serverID); err != nil {
+		t.Fatal(err)
+	}
+
+	for range 2 {
+		round, err := s.RecordRound(ctx, serverID, input)
+		if err != nil {
+			t.Fatalf("RecordRound: %v", err)
+		}
+
+		if round.ID == 0 || round.EndedAt.IsZero() {
+			t.Fatalf("got %+v", round)
+		}
+	}
+
+	maps, err := s.ListMaps(ctx)
+	if err != nil {
+		t.Fatalf("ListMaps: %v", err)
+	}
+
+	if len(maps) != 2 {
+		t.Fatalf("got %d maps, want 2", len(maps))
+	}
+
+	for _, st := range maps {
+		want := m.MapStats{Name: storagetest.PlayedMap, Rounds: 2, CTWins: 1, TWins: 1, AvgRoundLength: 100}
+		if st.Name == "de_mirage" {
+			want = m.MapStats{Name: "de_mirage", Rounds: 2, TWins: 2, AvgRoundLength: 80.5}
+		}
+
+		st.LastPlayed = want.LastPlayed
+		if st != want {
+			t.Errorf("got %+v, want %+v", st, want)
+		}
+	}
+
+	players, err := s.GetLeaderboard(ctx, "de_mirage", 10)
+	if err != nil {
+		t.Fatalf("GetLeaderboard: %v", err)
+	}
+
+	want := []m.MapPlayerStats{
+		{SteamID: storagetest.PlayerID, Rounds: 2, Kills: 6, Headshots: 4},
+		{SteamID: storagetest.AdminID, Rounds: 2, Deaths: 2},
+	}
+	if len(players) != len(want) {
+		t.Fatalf("got %+v, want %+v", players, want)
+	}
+
+	for i := range want {
+		if players[i] != want[i] {
+			t.Errorf("player %d: got %+v, want %+v", i, players[i], want[i])
+		}
+	}
+}
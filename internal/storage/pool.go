@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cs2-server/backend/config"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+const maxConnectBackoff = 30 * time.Second
+
+// NewPool sizes the pool from cfg without connecting, so nothing is left open
+// if wiring fails and the database does not have to be up yet.
+func NewPool(cfg config.Postgres) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("NewPool (1): %w", err)
+	}
+
+	poolConfig.LazyConnect = true
+	poolConfig.MaxConns = int32(cfg.MaxConns)
+	poolConfig.MinConns = int32(cfg.MinConns)
+	poolConfig.MaxConnLifetime = cfg.MaxConnLifetime
+	poolConfig.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolConfig.HealthCheckPeriod = cfg.HealthCheckPeriod
+
+	if cfg.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.StatementTimeout.Milliseconds(), 10)
+	}
+
+	db, err := pgxpool.ConnectConfig(context.Background(), poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("NewPool (2): %w", err)
+	}
+
+	return db, nil
+}
+
+// Wait pings db until it answers, backing off between attempts, so the
+// database may come up after the server does.
+func Wait(ctx context.Context, db *pgxpool.Pool, cfg config.Postgres, logger *logrus.Logger) error {
+	backoff := cfg.ConnectBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := db.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+
+		if attempt >= cfg.ConnectAttempts {
+			return fmt.Errorf("Wait: %d attempts: %w", attempt, err)
+		}
+
+		logger.WithError(err).Warnf("postgres unavailable, retrying in %s", backoff)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Wait: %w", ctx.Err())
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, maxConnectBackoff)
+	}
+}
@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type RCONStorage struct {
+	db *pgxpool.Pool
+}
+
+func NewRCONStorage(db *pgxpool.Pool) *RCONStorage {
+	return &RCONStorage{
+		db: db,
+	}
+}
+
+func (s *RCONStorage) CreateRCONLog(ctx context.Context, log m.RCONLog) error {
+	query := `
+        INSERT INTO rcon_logs (server_id, steam_id, command, output, error)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+
+	if _, err := s.db.Exec(ctx, query, log.ServerID, log.SteamID, log.Command, log.Output, log.Error); err != nil {
+		return fmt.Errorf("CreateRCONLog: %w", err)
+	}
+
+	return nil
+}
+
+func (s *RCONStorage) ListRCONLogs(ctx context.Context, serverID int64, limit int) ([]m.RCONLog, error) {
+	query := `
+        SELECT id, server_id, steam_id, command, output, error, created_at
+        FROM rcon_logs
+        WHERE server_id = $1
+        ORDER BY created_at DESC, id DESC
+        LIMIT $2
+    `
+
+	rows, err := s.db.Query(ctx, query, serverID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ListRCONLogs (1): %w", err)
+	}
+	defer rows.Close()
+
+	logs := []m.RCONLog{}
+	for rows.Next() {
+		var log m.RCONLog
+		if err := rows.Scan(&log.ID, &log.ServerID, &log.SteamID, &log.Command, &log.Output, &log.Error, &log.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ListRCONLogs (2): %w", err)
+		}
+
+		logs = append(logs, log)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListRCONLogs (3): %w", err)
+	}
+
+	return logs, nil
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const serverColumns = "id, name, host, port, rcon_password, created_at, updated_at"
+
+type ServerStorage struct {
+	db *pgxpool.Pool
+}
+
+func NewServerStorage(db *pgxpool.Pool) *ServerStorage {
+	return &ServerStorage{
+		db: db,
+	}
+}
+
+func (s *ServerStorage) ListServers(ctx context.Context) ([]m.Server, error) {
+	query := `
+        SELECT ` + serverColumns + `
+        FROM servers
+        ORDER BY id
+    `
+
+	rows, err := s.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("ListServers (1): %w", err)
+	}
+	defer rows.Close()
+
+	servers := []m.Server{}
+	for rows.Next() {
+		srv, err := scanServer(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListServers (2): %w", err)
+		}
+
+		servers = append(servers, srv)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListServers (3): %w", err)
+	}
+
+	return servers, nil
+}
+
+func (s *ServerStorage) GetServer(ctx context.Context, ID int64) (m.Server, error) {
+	query := `
+        SELECT ` + serverColumns + `
+        FROM servers
+        WHERE id = $1
+    `
+
+	srv, err := scanServer(s.db.QueryRow(ctx, query, ID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return m.Server{}, fmt.Errorf("GetServer: %w", m.ErrNotFound)
+		}
+
+		return m.Server{}, fmt.Errorf("GetServer: %w", err)
+	}
+
+	return srv, nil
+}
+
+func (s *ServerStorage) CreateServer(ctx context.Context, input m.ServerInput) (m.Server, error) {
+	query := `
+        INSERT INTO servers (name, host, port, rcon_password)
+        VALUES ($1, $2, $3, $4)
+        RETURNING ` + serverColumns
+
+	srv, err := scanServer(s.db.QueryRow(ctx, query, input.Name, input.Host, input.Port, input.RCONPassword))
+	if err != nil {
+		return m.Server{}, fmt.Errorf("CreateServer: %w", err)
+	}
+
+	return srv, nil
+}
+
+func (s *ServerStorage) UpdateServer(ctx context.Context, ID int64, input m.ServerInput) (m.Server, error) {
+	query := `
+        UPDATE servers
+        SET name = $2, host = $3, port = $4,
+            rcon_password = COALESCE(NULLIF($5, ''), rcon_password),
+            updated_at = now()
+        WHERE id = $1
+        RETURNING ` + serverColumns
+
+	srv, err := scanServer(s.db.QueryRow(ctx, query, ID, input.Name, input.Host, input.Port, input.RCONPassword))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return m.Server{}, fmt.Errorf("UpdateServer: %w", m.ErrNotFound)
+		}
+
+		return m.Server{}, fmt.Errorf("UpdateServer: %w", err)
+	}
+
+	return srv, nil
+}
+
+func (s *ServerStorage) DeleteServer(ctx context.Context, ID int64) error {
+	query := `
+        DELETE FROM servers
+        WHERE id = $1
+    `
+
+	tag, err := s.db.Exec(ctx, query, ID)
+	if err != nil {
+		return fmt.Errorf("DeleteServer: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("DeleteServer: %w", m.ErrNotFound)
+	}
+
+	return nil
+}
+
+func scanServer(row pgx.Row) (m.Server, error) {
+	var srv m.Server
+	if err := row.Scan(&srv.ID, &srv.Name, &srv.Host, &srv.Port, &srv.RCONPassword, &srv.CreatedAt, &srv.UpdatedAt); err != nil {
+		return m.Server{}, err
+	}
+
+	srv.RCON = srv.RCONPassword != ""
+
+	return srv, nil
+}
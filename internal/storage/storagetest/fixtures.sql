@@ -0,0 +1,20 @@
+INSERT INTO player_stats (steam_id, kills, deaths, headshots) VALUES
+    ('76561198000000001', 120, 80, 48),
+    ('76561198000000002', 15, 30, 0);
+
+INSERT INTO admins (steam_id, role) VALUES
+    ('76561198000000002', 'admin');
+
+INSERT INTO servers (name, host, port) VALUES
+    ('Dust II', '127.0.0.1', 27015);
+
+INSERT INTO bans (type, steam_id, reason, admin_id) VALUES
+    ('ban', '76561198000000003', 'cheating', '76561198000000002');
+
+INSERT INTO rounds (server_id, map, winner, duration_ms)
+SELECT id, 'de_dust2', winner, duration_ms
+FROM servers, (VALUES ('ct', 90000), ('t', 110000)) AS r (winner, duration_ms);
+
+INSERT INTO map_player_stats (map, steam_id, rounds, kills, deaths, headshots) VALUES
+    ('de_dust2', '76561198000000001', 2, 5, 1, 2),
+    ('de_dust2', '76561198000000002', 2, 1, 3, 0);
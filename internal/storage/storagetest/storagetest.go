@@ -0,0 +1,101 @@
+// Package storagetest gives integration tests a migrated Postgres schema of
+// their own. Tests are skipped unless TEST_PG_DSN points at a database they
+// may create schemas in.
+package storagetest
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/cs2-server/backend/migrations"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// DSNEnv names the variable holding the database tests connect to.
+const DSNEnv = "TEST_PG_DSN"
+
+// Players in the fixtures.
+const (
+	PlayerID = "76561198000000001"
+	AdminID  = "76561198000000002"
+	BannedID = "76561198000000003"
+	// UnknownID has no stats.
+	UnknownID = "76561198000000009"
+)
+
+// PlayedMap has rounds in the fixtures.
+const PlayedMap = "de_dust2"
+
+//go:embed fixtures.sql
+var fixtures string
+
+// New creates a schema, migrates it and returns a pool whose connections use
+// it. The schema is dropped when the test ends.
+func New(t testing.TB) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv(DSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", DSNEnv)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	admin, err := pgxpool.Connect(ctx, dsn)
+	if err == nil {
+		err = admin.Ping(ctx)
+	}
+	if err != nil {
+		t.Skipf("postgres unavailable: %v", err)
+	}
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		admin.Close()
+		t.Fatalf("create schema: %v", err)
+	}
+
+	t.Cleanup(func() {
+		defer admin.Close()
+
+		if _, err := admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+	})
+
+	cfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("parse dsn: %v", err)
+	}
+
+	cfg.ConnConfig.RuntimeParams["search_path"] = schema
+
+	db, err := pgxpool.ConnectConfig(ctx, cfg)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+
+	t.Cleanup(db.Close)
+
+	if _, err := migrations.Up(ctx, db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	return db
+}
+
+// Load inserts the fixture players, admin, server, ban and two rounds on
+// PlayedMap.
+func Load(t testing.TB, db *pgxpool.Pool) {
+	t.Helper()
+
+	if _, err := db.Exec(context.Background(), fixtures); err != nil {
+		t.Fatalf("fixtures: %v", err)
+	}
+}
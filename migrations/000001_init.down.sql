@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS admins;
+DROP TABLE IF EXISTS player_stats;
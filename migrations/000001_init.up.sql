@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS player_stats (
+    steam_id  TEXT PRIMARY KEY,
+    kills     INTEGER NOT NULL DEFAULT 0,
+    deaths    INTEGER NOT NULL DEFAULT 0,
+    headshots INTEGER NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS admins (
+    steam_id   TEXT PRIMARY KEY,
+    role       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS servers (
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT NOT NULL,
+    host       TEXT NOT NULL,
+    port       INTEGER NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (host, port)
+);
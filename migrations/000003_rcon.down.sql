@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS rcon_logs;
+
+ALTER TABLE servers DROP COLUMN IF EXISTS rcon_password;
@@ -0,0 +1,13 @@
+ALTER TABLE servers ADD COLUMN IF NOT EXISTS rcon_password TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS rcon_logs (
+    id         BIGSERIAL PRIMARY KEY,
+    server_id  BIGINT NOT NULL REFERENCES servers (id) ON DELETE CASCADE,
+    steam_id   TEXT NOT NULL,
+    command    TEXT NOT NULL,
+    output     TEXT NOT NULL DEFAULT '',
+    error      TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS rcon_logs_server_id_idx ON rcon_logs (server_id, created_at DESC);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS bans (
+    id          BIGSERIAL PRIMARY KEY,
+    type        TEXT NOT NULL,
+    steam_id    TEXT NOT NULL,
+    ip          TEXT NOT NULL DEFAULT '',
+    reason      TEXT NOT NULL,
+    admin_id    TEXT NOT NULL,
+    duration    BIGINT NOT NULL DEFAULT 0,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at  TIMESTAMPTZ,
+    lifted_at   TIMESTAMPTZ,
+    lifted_by   TEXT NOT NULL DEFAULT '',
+    lift_reason TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS bans_steam_id_idx ON bans (steam_id);
+CREATE INDEX IF NOT EXISTS bans_ip_idx ON bans (ip) WHERE ip <> '';
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id         BIGSERIAL PRIMARY KEY,
+    server_id  BIGINT NOT NULL REFERENCES servers (id) ON DELETE CASCADE,
+    prefix     TEXT NOT NULL,
+    key_hash   TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS api_keys_server_id_idx;
+
+ALTER TABLE api_keys
+    DROP COLUMN IF EXISTS revoked_at,
+    DROP COLUMN IF EXISTS last_used_ip,
+    DROP COLUMN IF EXISTS last_used_at,
+    DROP COLUMN IF EXISTS scopes,
+    DROP COLUMN IF EXISTS name;
@@ -0,0 +1,11 @@
+-- Keys issued before scopes existed were only good for ban checks.
+ALTER TABLE api_keys
+    ADD COLUMN IF NOT EXISTS name         TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS scopes       TEXT[] NOT NULL DEFAULT '{bans:check}',
+    ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS last_used_ip TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS revoked_at   TIMESTAMPTZ;
+
+ALTER TABLE api_keys ALTER COLUMN scopes SET DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS api_keys_server_id_idx ON api_keys (server_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS map_player_stats;
+DROP TABLE IF EXISTS rounds;
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS rounds (
+    id          BIGSERIAL PRIMARY KEY,
+    server_id   BIGINT REFERENCES servers (id) ON DELETE SET NULL,
+    map         TEXT NOT NULL,
+    winner      TEXT NOT NULL,
+    duration_ms BIGINT NOT NULL,
+    ended_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS rounds_map_idx ON rounds (map);
+
+CREATE TABLE IF NOT EXISTS map_player_stats (
+    map       TEXT NOT NULL,
+    steam_id  TEXT NOT NULL,
+    rounds    INTEGER NOT NULL DEFAULT 0,
+    kills     INTEGER NOT NULL DEFAULT 0,
+    deaths    INTEGER NOT NULL DEFAULT 0,
+    headshots INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (map, steam_id)
+);
+
+CREATE INDEX IF NOT EXISTS map_player_stats_kills_idx ON map_player_stats (map, kills DESC);
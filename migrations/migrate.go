@@ -0,0 +1,142 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// lockID keys the advisory lock that keeps two replicas from migrating at
+// the same time.
+const lockID = 7309187
+
+var ErrDirty = errors.New("schema is dirty, fix it by hand and reset schema_migrations")
+
+// Up applies the migrations newer than the schema version, each in its own
+// transaction, and returns the versions applied. The version is recorded in
+// schema_migrations as golang-migrate does, so either tool can be used.
+func Up(ctx context.Context, db *pgxpool.Pool) ([]uint, error) {
+	all, err := list()
+	if err != nil {
+		return nil, fmt.Errorf("Up (1): %w", err)
+	}
+
+	var applied []uint
+
+	for _, m := range all {
+		sql, err := m.up()
+		if err != nil {
+			return applied, fmt.Errorf("Up (2): %w", err)
+		}
+
+		ok, err := step(ctx, db, func(current uint, exists bool) (bool, uint) {
+			return !exists || m.version > current, m.version
+		}, sql)
+		if err != nil {
+			return applied, fmt.Errorf("Up (3): %s: %w", m.name, err)
+		}
+
+		if ok {
+			applied = append(applied, m.version)
+		}
+	}
+
+	return applied, nil
+}
+
+// Down reverts the newest steps migrations applied and returns their
+// versions.
+func Down(ctx context.Context, db *pgxpool.Pool, steps int) ([]uint, error) {
+	all, err := list()
+	if err != nil {
+		return nil, fmt.Errorf("Down (1): %w", err)
+	}
+
+	var reverted []uint
+
+	for i := len(all) - 1; i >= 0 && len(reverted) < steps; i-- {
+		m := all[i]
+
+		var previous uint
+		if i > 0 {
+			previous = all[i-1].version
+		}
+
+		sql, err := m.down()
+		if err != nil {
+			return reverted, fmt.Errorf("Down (2): %w", err)
+		}
+
+		ok, err := step(ctx, db, func(current uint, exists bool) (bool, uint) {
+			return exists && current == m.version, previous
+		}, sql)
+		if err != nil {
+			return reverted, fmt.Errorf("Down (3): %s: %w", m.name, err)
+		}
+
+		if ok {
+			reverted = append(reverted, m.version)
+		}
+	}
+
+	return reverted, nil
+}
+
+// step runs sql and records the version it leads to in one transaction, if
+// apply says so given the current version. Version 0 clears the record.
+func step(ctx context.Context, db *pgxpool.Pool, apply func(current uint, exists bool) (bool, uint), sql string) (bool, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock($1)`, lockID); err != nil {
+		return false, err
+	}
+
+	if _, err := tx.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)`); err != nil {
+		return false, err
+	}
+
+	var (
+		current int64
+		dirty   bool
+		exists  = true
+	)
+
+	err = tx.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&current, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		exists = false
+	} else if err != nil {
+		return false, err
+	}
+
+	if dirty {
+		return false, ErrDirty
+	}
+
+	ok, next := apply(uint(current), exists)
+	if !ok {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx, sql); err != nil {
+		return false, err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM schema_migrations`); err != nil {
+		return false, err
+	}
+
+	if next > 0 {
+		if _, err := tx.Exec(ctx, `INSERT INTO schema_migrations (version, dirty) VALUES ($1, false)`, int64(next)); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit(ctx)
+}
@@ -0,0 +1,73 @@
+// Package migrations embeds the SQL migrations, so the binary knows which
+// schema version it needs, and applies them.
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+// migration is a pair of NNNNNN_name.up.sql and NNNNNN_name.down.sql files.
+type migration struct {
+	version uint
+	name    string
+}
+
+func (m migration) up() (string, error) {
+	b, err := FS.ReadFile(m.name + ".up.sql")
+
+	return string(b), err
+}
+
+func (m migration) down() (string, error) {
+	b, err := FS.ReadFile(m.name + ".down.sql")
+
+	return string(b), err
+}
+
+// list returns the migrations ordered by version.
+func list() ([]migration, error) {
+	names, err := fs.Glob(FS, "*.up.sql")
+	if err != nil {
+		return nil, fmt.Errorf("list (1): %w", err)
+	}
+
+	var out []migration
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(name, "_")
+
+		v, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("list (2): invalid migration name %q", name)
+		}
+
+		out = append(out, migration{version: uint(v), name: strings.TrimSuffix(name, ".up.sql")})
+	}
+
+	slices.SortFunc(out, func(a, b migration) int {
+		return int(a.version) - int(b.version)
+	})
+
+	return out, nil
+}
+
+// Latest returns the version of the newest migration.
+func Latest() (uint, error) {
+	all, err := list()
+	if err != nil {
+		return 0, fmt.Errorf("Latest: %w", err)
+	}
+
+	if len(all) == 0 {
+		return 0, nil
+	}
+
+	return all[len(all)-1].version, nil
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import "testing"
+
+func TestList(t *testing.T) {
+	all, err := list()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, m := range all {
+		if m.version != uint(i+1) {
+			t.Errorf("migration %s has version %d, want %d", m.name, m.version, i+1)
+		}
+
+		if _, err := m.down(); err != nil {
+			t.Errorf("migration %s: %v", m.name, err)
+		}
+	}
+
+	latest, err := Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if latest != all[len(all)-1].version {
+		t.Errorf("latest %d", latest)
+	}
+}
@@ -0,0 +1,470 @@
+package a2s
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	headerSingle int32 = -1
+	headerSplit  int32 = -2
+
+	typeInfoRequest    byte = 'T'
+	typeInfoResponse   byte = 'I'
+	typePlayerRequest  byte = 'U'
+	typePlayerResponse byte = 'D'
+	typeChallenge      byte = 'A'
+
+	infoPayload = "Source Engine Query\x00"
+
+	edfPort     byte = 0x80
+	edfSteamID  byte = 0x10
+	edfSourceTV byte = 0x40
+	edfKeywords byte = 0x20
+	edfGameID   byte = 0x01
+
+	noChallenge int32 = -1
+)
+
+var (
+	ErrShortPacket    = errors.New("packet is too short")
+	ErrInvalidHeader  = errors.New("invalid packet header")
+	ErrUnexpectedType = errors.New("unexpected response type")
+)
+
+type Info struct {
+	Protocol    byte   `json:"protocol"`
+	Name        string `json:"name"`
+	Map         string `json:"map"`
+	Folder      string `json:"folder"`
+	Game        string `json:"game"`
+	AppID       uint16 `json:"app_id"`
+	Players     int    `json:"players"`
+	MaxPlayers  int    `json:"max_players"`
+	Bots        int    `json:"bots"`
+	ServerType  byte   `json:"server_type"`
+	Environment byte   `json:"environment"`
+	Visibility  bool   `json:"visibility"`
+	VAC         bool   `json:"vac"`
+	Version     string `json:"version"`
+	Port        uint16 `json:"port,omitempty"`
+	SteamID     uint64 `json:"steam_id,omitempty"`
+	Keywords    string `json:"keywords,omitempty"`
+	GameID      uint64 `json:"game_id,omitempty"`
+}
+
+type Player struct {
+	Index    byte    `json:"index"`
+	Name     string  `json:"name"`
+	Score    int32   `json:"score"`
+	Duration float32 `json:"duration"`
+}
+
+// EncodeInfoRequest builds an A2S_INFO request. A zero challenge means the
+// server has not asked for one yet.
+func EncodeInfoRequest(challenge int32) []byte {
+	b := appendHeader(nil, typeInfoRequest)
+	b = append(b, infoPayload...)
+
+	if challenge != 0 {
+		b = binary.LittleEndian.AppendUint32(b, uint32(challenge))
+	}
+
+	return b
+}
+
+// EncodePlayerRequest builds an A2S_PLAYER request. A zero challenge asks the
+// server to issue one.
+func EncodePlayerRequest(challenge int32) []byte {
+	if challenge == 0 {
+		challenge = noChallenge
+	}
+
+	b := appendHeader(nil, typePlayerRequest)
+
+	return binary.LittleEndian.AppendUint32(b, uint32(challenge))
+}
+
+// EncodeChallenge builds an S2C_CHALLENGE response.
+func EncodeChallenge(challenge int32) []byte {
+	b := appendHeader(nil, typeChallenge)
+
+	return binary.LittleEndian.AppendUint32(b, uint32(challenge))
+}
+
+// EncodeInfo builds an A2S_INFO response. Optional fields are written only
+// when they are set.
+func EncodeInfo(info Info) []byte {
+	b := appendHeader(nil, typeInfoResponse)
+	b = append(b, info.Protocol)
+	b = appendString(b, info.Name)
+	b = appendString(b, info.Map)
+	b = appendString(b, info.Folder)
+	b = appendString(b, info.Game)
+	b = binary.LittleEndian.AppendUint16(b, info.AppID)
+	b = append(b, byte(info.Players), byte(info.MaxPlayers), byte(info.Bots), info.ServerType, info.Environment)
+	b = append(b, boolByte(info.Visibility), boolByte(info.VAC))
+	b = appendString(b, info.Version)
+
+	var edf byte
+	if info.Port != 0 {
+		edf |= edfPort
+	}
+	if info.SteamID != 0 {
+		edf |= edfSteamID
+	}
+	if info.Keywords != "" {
+		edf |= edfKeywords
+	}
+	if info.GameID != 0 {
+		edf |= edfGameID
+	}
+
+	b = append(b, edf)
+
+	if edf&edfPort != 0 {
+		b = binary.LittleEndian.AppendUint16(b, info.Port)
+	}
+	if edf&edfSteamID != 0 {
+		b = binary.LittleEndian.AppendUint64(b, info.SteamID)
+	}
+	if edf&edfKeywords != 0 {
+		b = appendString(b, info.Keywords)
+	}
+	if edf&edfGameID != 0 {
+		b = binary.LittleEndian.AppendUint64(b, info.GameID)
+	}
+
+	return b
+}
+
+// EncodePlayers builds an A2S_PLAYER response.
+func EncodePlayers(players []Player) []byte {
+	b := appendHeader(nil, typePlayerResponse)
+	b = append(b, byte(len(players)))
+
+	for _, p := range players {
+		b = append(b, p.Index)
+		b = appendString(b, p.Name)
+		b = binary.LittleEndian.AppendUint32(b, uint32(p.Score))
+		b = binary.LittleEndian.AppendUint32(b, math.Float32bits(p.Duration))
+	}
+
+	return b
+}
+
+// DecodeChallenge returns the challenge number if the packet is an
+// S2C_CHALLENGE response.
+func DecodeChallenge(packet []byte) (int32, bool) {
+	r, typ, err := newReader(packet)
+	if err != nil || typ != typeChallenge {
+		return 0, false
+	}
+
+	challenge, err := r.int32()
+	if err != nil {
+		return 0, false
+	}
+
+	return challenge, true
+}
+
+// DecodeInfoRequest returns the challenge attached to an A2S_INFO request.
+func DecodeInfoRequest(packet []byte) (int32, error) {
+	r, typ, err := newReader(packet)
+	if err != nil {
+		return 0, fmt.Errorf("DecodeInfoRequest (1): %w", err)
+	}
+
+	if typ != typeInfoRequest {
+		return 0, fmt.Errorf("DecodeInfoRequest (2): %w", ErrUnexpectedType)
+	}
+
+	payload, err := r.string()
+	if err != nil || payload+"\x00" != infoPayload {
+		return 0, fmt.Errorf("DecodeInfoRequest (3): %w", ErrInvalidHeader)
+	}
+
+	if r.len() == 0 {
+		return 0, nil
+	}
+
+	challenge, err := r.int32()
+	if err != nil {
+		return 0, fmt.Errorf("DecodeInfoRequest (4): %w", err)
+	}
+
+	return challenge, nil
+}
+
+// DecodePlayerRequest returns the challenge attached to an A2S_PLAYER request.
+func DecodePlayerRequest(packet []byte) (int32, error) {
+	r, typ, err := newReader(packet)
+	if err != nil {
+		return 0, fmt.Errorf("DecodePlayerRequest (1): %w", err)
+	}
+
+	if typ != typePlayerRequest {
+		return 0, fmt.Errorf("DecodePlayerRequest (2): %w", ErrUnexpectedType)
+	}
+
+	challenge, err := r.int32()
+	if err != nil {
+		return 0, fmt.Errorf("DecodePlayerRequest (3): %w", err)
+	}
+
+	if challenge == noChallenge {
+		return 0, nil
+	}
+
+	return challenge, nil
+}
+
+// DecodeInfo parses an A2S_INFO response.
+func DecodeInfo(packet []byte) (Info, error) {
+	r, typ, err := newReader(packet)
+	if err != nil {
+		return Info{}, fmt.Errorf("DecodeInfo (1): %w", err)
+	}
+
+	if typ != typeInfoResponse {
+		return Info{}, fmt.Errorf("DecodeInfo (2): %w", ErrUnexpectedType)
+	}
+
+	var info Info
+
+	if info.Protocol, err = r.byte(); err != nil {
+		return Info{}, fmt.Errorf("DecodeInfo (3): %w", err)
+	}
+
+	for _, s := range []*string{&info.Name, &info.Map, &info.Folder, &info.Game} {
+		if *s, err = r.string(); err != nil {
+			return Info{}, fmt.Errorf("DecodeInfo (4): %w", err)
+		}
+	}
+
+	if info.AppID, err = r.uint16(); err != nil {
+		return Info{}, fmt.Errorf("DecodeInfo (5): %w", err)
+	}
+
+	fixed, err := r.bytes(7)
+	if err != nil {
+		return Info{}, fmt.Errorf("DecodeInfo (6): %w", err)
+	}
+
+	info.Players = int(fixed[0])
+	info.MaxPlayers = int(fixed[1])
+	info.Bots = int(fixed[2])
+	info.ServerType = fixed[3]
+	info.Environment = fixed[4]
+	info.Visibility = fixed[5] != 0
+	info.VAC = fixed[6] != 0
+
+	if info.Version, err = r.string(); err != nil {
+		return Info{}, fmt.Errorf("DecodeInfo (7): %w", err)
+	}
+
+	if r.len() == 0 {
+		return info, nil
+	}
+
+	edf, err := r.byte()
+	if err != nil {
+		return Info{}, fmt.Errorf("DecodeInfo (8): %w", err)
+	}
+
+	if edf&edfPort != 0 {
+		if info.Port, err = r.uint16(); err != nil {
+			return Info{}, fmt.Errorf("DecodeInfo (9): %w", err)
+		}
+	}
+
+	if edf&edfSteamID != 0 {
+		if info.SteamID, err = r.uint64(); err != nil {
+			return Info{}, fmt.Errorf("DecodeInfo (10): %w", err)
+		}
+	}
+
+	if edf&edfSourceTV != 0 {
+		if _, err = r.uint16(); err != nil {
+			return Info{}, fmt.Errorf("DecodeInfo (11): %w", err)
+		}
+		if _, err = r.string(); err != nil {
+			return Info{}, fmt.Errorf("DecodeInfo (12): %w", err)
+		}
+	}
+
+	if edf&edfKeywords != 0 {
+		if info.Keywords, err = r.string(); err != nil {
+			return Info{}, fmt.Errorf("DecodeInfo (13): %w", err)
+		}
+	}
+
+	if edf&edfGameID != 0 {
+		if info.GameID, err = r.uint64(); err != nil {
+			return Info{}, fmt.Errorf("DecodeInfo (14): %w", err)
+		}
+	}
+
+	return info, nil
+}
+
+// DecodePlayers parses an A2S_PLAYER response.
+func DecodePlayers(packet []byte) ([]Player, error) {
+	r, typ, err := newReader(packet)
+	if err != nil {
+		return nil, fmt.Errorf("DecodePlayers (1): %w", err)
+	}
+
+	if typ != typePlayerResponse {
+		return nil, fmt.Errorf("DecodePlayers (2): %w", ErrUnexpectedType)
+	}
+
+	count, err := r.byte()
+	if err != nil {
+		return nil, fmt.Errorf("DecodePlayers (3): %w", err)
+	}
+
+	players := make([]Player, 0, count)
+
+	for i := 0; i < int(count); i++ {
+		var p Player
+
+		if p.Index, err = r.byte(); err != nil {
+			return nil, fmt.Errorf("DecodePlayers (4): %w", err)
+		}
+
+		if p.Name, err = r.string(); err != nil {
+			return nil, fmt.Errorf("DecodePlayers (5): %w", err)
+		}
+
+		if p.Score, err = r.int32(); err != nil {
+			return nil, fmt.Errorf("DecodePlayers (6): %w", err)
+		}
+
+		duration, err := r.uint32()
+		if err != nil {
+			return nil, fmt.Errorf("DecodePlayers (7): %w", err)
+		}
+
+		p.Duration = math.Float32frombits(duration)
+
+		players = append(players, p)
+	}
+
+	return players, nil
+}
+
+func appendHeader(b []byte, typ byte) []byte {
+	return append(b, 0xff, 0xff, 0xff, 0xff, typ)
+}
+
+func appendString(b []byte, s string) []byte {
+	b = append(b, s...)
+
+	return append(b, 0)
+}
+
+func boolByte(v bool) byte {
+	if v {
+		return 1
+	}
+
+	return 0
+}
+
+type reader struct {
+	buf []byte
+}
+
+func newReader(packet []byte) (*reader, byte, error) {
+	r := &reader{buf: packet}
+
+	header, err := r.int32()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if header != headerSingle {
+		return nil, 0, ErrInvalidHeader
+	}
+
+	typ, err := r.byte()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return r, typ, nil
+}
+
+func (r *reader) len() int {
+	return len(r.buf)
+}
+
+func (r *reader) bytes(n int) ([]byte, error) {
+	if len(r.buf) < n {
+		return nil, ErrShortPacket
+	}
+
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+
+	return b, nil
+}
+
+func (r *reader) byte() (byte, error) {
+	b, err := r.bytes(1)
+	if err != nil {
+		return 0, err
+	}
+
+	return b[0], nil
+}
+
+func (r *reader) uint16() (uint16, error) {
+	b, err := r.bytes(2)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+func (r *reader) uint32() (uint32, error) {
+	b, err := r.bytes(4)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (r *reader) int32() (int32, error) {
+	v, err := r.uint32()
+
+	return int32(v), err
+}
+
+func (r *reader) uint64() (uint64, error) {
+	b, err := r.bytes(8)
+	if err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func (r *reader) string() (string, error) {
+	i := bytes.IndexByte(r.buf, 0)
+	if i < 0 {
+		return "", ErrShortPacket
+	}
+
+	s := string(r.buf[:i])
+	r.buf = r.buf[i+1:]
+
+	return s, nil
+}
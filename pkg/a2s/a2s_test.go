@@ -0,0 +1,232 @@
+package a2s
+
+import (
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestInfoRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		info Info
+	}{
+		{
+			name: "without extra data",
+			info: Info{
+				Protocol:   17,
+				Name:       "cs2 #1",
+				Map:        "de_mirage",
+				Folder:     "csgo",
+				Game:       "Counter-Strike 2",
+				AppID:      730,
+				Players:    7,
+				MaxPlayers: 10,
+				ServerType: 'd',
+				VAC:        true,
+				Version:    "1.40.2.2",
+			},
+		},
+		{
+			name: "with extra data",
+			info: Info{
+				Protocol:    17,
+				Name:        "cs2 #2",
+				Map:         "de_inferno",
+				Folder:      "csgo",
+				Game:        "Counter-Strike 2",
+				AppID:       730,
+				Players:     2,
+				MaxPlayers:  12,
+				Bots:        1,
+				ServerType:  'd',
+				Environment: 'l',
+				Visibility:  true,
+				Version:     "1.40.2.2",
+				Port:        27015,
+				SteamID:     90012345678901234,
+				Keywords:    "secure,empty",
+				GameID:      730,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeInfo(EncodeInfo(tt.info))
+			if err != nil {
+				t.Fatalf("DecodeInfo: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.info) {
+				t.Errorf("got %+v, want %+v", got, tt.info)
+			}
+		})
+	}
+}
+
+func TestDecodeInfoSourceTV(t *testing.T) {
+	b := EncodeInfo(Info{Name: "tv", Map: "de_nuke"})
+	b[len(b)-1] = edfSourceTV | edfKeywords
+	b = append(b, 0x88, 0x13)
+	b = appendString(b, "SourceTV")
+	b = appendString(b, "tv")
+
+	got, err := DecodeInfo(b)
+	if err != nil {
+		t.Fatalf("DecodeInfo: %v", err)
+	}
+
+	if got.Keywords != "tv" || got.Map != "de_nuke" {
+		t.Errorf("unexpected info: %+v", got)
+	}
+}
+
+func TestPlayersRoundTrip(t *testing.T) {
+	players := []Player{
+		{Index: 0, Name: "s1mple", Score: 31, Duration: 1234.5},
+		{Index: 1, Name: "", Score: -2, Duration: 1},
+	}
+
+	got, err := DecodePlayers(EncodePlayers(players))
+	if err != nil {
+		t.Fatalf("DecodePlayers: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, players) {
+		t.Errorf("got %+v, want %+v", got, players)
+	}
+
+	got, err = DecodePlayers(EncodePlayers(nil))
+	if err != nil {
+		t.Fatalf("DecodePlayers: %v", err)
+	}
+
+	if len(got) != 0 {
+		t.Errorf("got %d players, want 0", len(got))
+	}
+}
+
+func TestRequests(t *testing.T) {
+	challenge, err := DecodeInfoRequest(EncodeInfoRequest(0))
+	if err != nil || challenge != 0 {
+		t.Errorf("info request without challenge: %d, %v", challenge, err)
+	}
+
+	challenge, err = DecodeInfoRequest(EncodeInfoRequest(42))
+	if err != nil || challenge != 42 {
+		t.Errorf("info request with challenge: %d, %v", challenge, err)
+	}
+
+	challenge, err = DecodePlayerRequest(EncodePlayerRequest(0))
+	if err != nil || challenge != 0 {
+		t.Errorf("player request without challenge: %d, %v", challenge, err)
+	}
+
+	challenge, err = DecodePlayerRequest(EncodePlayerRequest(42))
+	if err != nil || challenge != 42 {
+		t.Errorf("player request with challenge: %d, %v", challenge, err)
+	}
+
+	challenge, ok := DecodeChallenge(EncodeChallenge(-7))
+	if !ok || challenge != -7 {
+		t.Errorf("challenge: %d, %v", challenge, ok)
+	}
+
+	if _, ok := DecodeChallenge(EncodeInfo(Info{})); ok {
+		t.Error("info response decoded as challenge")
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	info := EncodeInfo(Info{Name: "cs2", Map: "de_dust2", Version: "1"})
+
+	tests := []struct {
+		name   string
+		packet []byte
+		decode func([]byte) error
+		want   error
+	}{
+		{
+			name:   "empty",
+			packet: nil,
+			decode: func(b []byte) error { _, err := DecodeInfo(b); return err },
+			want:   ErrShortPacket,
+		},
+		{
+			name:   "bad header",
+			packet: append([]byte{0, 0, 0, 0}, info[4:]...),
+			decode: func(b []byte) error { _, err := DecodeInfo(b); return err },
+			want:   ErrInvalidHeader,
+		},
+		{
+			name:   "truncated info",
+			packet: info[:len(info)-4],
+			decode: func(b []byte) error { _, err := DecodeInfo(b); return err },
+			want:   ErrShortPacket,
+		},
+		{
+			name:   "players as info",
+			packet: EncodePlayers(nil),
+			decode: func(b []byte) error { _, err := DecodeInfo(b); return err },
+			want:   ErrUnexpectedType,
+		},
+		{
+			name:   "truncated players",
+			packet: EncodePlayers([]Player{{Name: "a"}})[:8],
+			decode: func(b []byte) error { _, err := DecodePlayers(b); return err },
+			want:   ErrShortPacket,
+		},
+		{
+			name:   "info as players",
+			packet: info,
+			decode: func(b []byte) error { _, err := DecodePlayers(b); return err },
+			want:   ErrUnexpectedType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.decode(tt.packet); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadSplitPacket(t *testing.T) {
+	packet := EncodePlayers([]Player{{Name: "first"}, {Name: "second"}})
+	half := len(packet) / 2
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go func() {
+		// Parts are sent out of order on purpose.
+		server.Write(splitPart(7, 2, 1, packet[half:]))
+		server.Write(splitPart(7, 2, 0, packet[:half]))
+	}()
+
+	got, err := readPacket(client)
+	if err != nil {
+		t.Fatalf("readPacket: %v", err)
+	}
+
+	players, err := DecodePlayers(got)
+	if err != nil {
+		t.Fatalf("DecodePlayers: %v", err)
+	}
+
+	if len(players) != 2 || players[1].Name != "second" {
+		t.Errorf("unexpected players: %+v", players)
+	}
+}
+
+func splitPart(id uint32, total, number byte, payload []byte) []byte {
+	b := []byte{0xfe, 0xff, 0xff, 0xff, byte(id), byte(id >> 8), byte(id >> 16), byte(id >> 24), total, number}
+	b = append(b, byte(len(payload)), byte(len(payload)>>8))
+
+	return append(b, payload...)
+}
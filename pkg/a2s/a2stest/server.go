@@ -0,0 +1,105 @@
+// Package a2stest provides a local UDP server that answers A2S queries, for
+// use in tests.
+package a2stest
+
+import (
+	"net"
+	"sync"
+
+	"github.com/cs2-server/backend/pkg/a2s"
+)
+
+const challenge int32 = 0x4b1d
+
+type Server struct {
+	conn net.PacketConn
+
+	mu        sync.RWMutex
+	info      a2s.Info
+	players   []a2s.Player
+	challenge bool
+
+	wg sync.WaitGroup
+}
+
+// NewServer starts a server on a random local port. When challenge is set the
+// server answers every request without the right challenge number with
+// S2C_CHALLENGE, the way CS2 servers do.
+func NewServer(info a2s.Info, players []a2s.Player, challenge bool) (*Server, error) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		conn:      conn,
+		info:      info,
+		players:   players,
+		challenge: challenge,
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+func (s *Server) Addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+// Set replaces the state the server reports.
+func (s *Server) Set(info a2s.Info, players []a2s.Player) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.info = info
+	s.players = players
+}
+
+func (s *Server) Close() error {
+	err := s.conn.Close()
+	s.wg.Wait()
+
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	buf := make([]byte, 1400)
+
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		if resp := s.handle(buf[:n]); resp != nil {
+			s.conn.WriteTo(resp, addr)
+		}
+	}
+}
+
+func (s *Server) handle(packet []byte) []byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if got, err := a2s.DecodeInfoRequest(packet); err == nil {
+		if s.challenge && got != challenge {
+			return a2s.EncodeChallenge(challenge)
+		}
+
+		return a2s.EncodeInfo(s.info)
+	}
+
+	if got, err := a2s.DecodePlayerRequest(packet); err == nil {
+		if s.challenge && got != challenge {
+			return a2s.EncodeChallenge(challenge)
+		}
+
+		return a2s.EncodePlayers(s.players)
+	}
+
+	return nil
+}
@@ -0,0 +1,166 @@
+package a2s
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+const (
+	maxPacketSize = 1400
+	splitHeader   = 12
+	maxChallenges = 3
+)
+
+var (
+	ErrChallengeLoop = errors.New("server keeps answering with a challenge")
+)
+
+type Client struct {
+	timeout time.Duration
+}
+
+func NewClient(timeout time.Duration) *Client {
+	return &Client{
+		timeout: timeout,
+	}
+}
+
+// Info queries A2S_INFO from the server at addr.
+func (c *Client) Info(ctx context.Context, addr string) (Info, error) {
+	packet, err := c.query(ctx, addr, EncodeInfoRequest)
+	if err != nil {
+		return Info{}, fmt.Errorf("Info (1): %w", err)
+	}
+
+	info, err := DecodeInfo(packet)
+	if err != nil {
+		return Info{}, fmt.Errorf("Info (2): %w", err)
+	}
+
+	return info, nil
+}
+
+// Players queries A2S_PLAYER from the server at addr.
+func (c *Client) Players(ctx context.Context, addr string) ([]Player, error) {
+	packet, err := c.query(ctx, addr, EncodePlayerRequest)
+	if err != nil {
+		return nil, fmt.Errorf("Players (1): %w", err)
+	}
+
+	players, err := DecodePlayers(packet)
+	if err != nil {
+		return nil, fmt.Errorf("Players (2): %w", err)
+	}
+
+	return players, nil
+}
+
+// query sends a request built by encode, repeating it with the challenge
+// number for as long as the server answers with S2C_CHALLENGE.
+func (c *Client) query(ctx context.Context, addr string, encode func(int32) []byte) ([]byte, error) {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("query (1): %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err := conn.SetDeadline(deadline); err != nil {
+		return nil, fmt.Errorf("query (2): %w", err)
+	}
+
+	var challenge int32
+
+	for i := 0; i < maxChallenges; i++ {
+		if _, err := conn.Write(encode(challenge)); err != nil {
+			return nil, fmt.Errorf("query (3): %w", err)
+		}
+
+		packet, err := readPacket(conn)
+		if err != nil {
+			return nil, fmt.Errorf("query (4): %w", err)
+		}
+
+		next, ok := DecodeChallenge(packet)
+		if !ok {
+			return packet, nil
+		}
+
+		challenge = next
+	}
+
+	return nil, fmt.Errorf("query (5): %w", ErrChallengeLoop)
+}
+
+// readPacket reads a single response, reassembling it if the server split it
+// across several datagrams.
+func readPacket(conn net.Conn) ([]byte, error) {
+	buf := make([]byte, maxPacketSize)
+
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if n < 4 {
+		return nil, ErrShortPacket
+	}
+
+	if int32(binary.LittleEndian.Uint32(buf)) != headerSplit {
+		return buf[:n], nil
+	}
+
+	var (
+		id     uint32
+		parts  [][]byte
+		filled int
+	)
+
+	for {
+		if n < splitHeader {
+			return nil, ErrShortPacket
+		}
+
+		packetID := binary.LittleEndian.Uint32(buf[4:])
+		total, number := int(buf[8]), int(buf[9])
+
+		if parts == nil {
+			id = packetID
+			parts = make([][]byte, total)
+		}
+
+		if packetID != id || total != len(parts) || number >= total {
+			return nil, ErrInvalidHeader
+		}
+
+		if parts[number] == nil {
+			parts[number] = append([]byte(nil), buf[splitHeader:n]...)
+			filled++
+		}
+
+		if filled == len(parts) {
+			break
+		}
+
+		if n, err = conn.Read(buf); err != nil {
+			return nil, err
+		}
+	}
+
+	var packet []byte
+	for _, p := range parts {
+		packet = append(packet, p...)
+	}
+
+	return packet, nil
+}
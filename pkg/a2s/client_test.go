@@ -0,0 +1,58 @@
+package a2s_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/cs2-server/backend/pkg/a2s"
+	"github.com/cs2-server/backend/pkg/a2s/a2stest"
+)
+
+func TestClient(t *testing.T) {
+	info := a2s.Info{Name: "cs2", Map: "de_ancient", Players: 1, MaxPlayers: 10, Version: "1"}
+	players := []a2s.Player{{Name: "player", Score: 3, Duration: 60}}
+
+	for _, challenge := range []bool{false, true} {
+		srv, err := a2stest.NewServer(info, players, challenge)
+		if err != nil {
+			t.Fatalf("NewServer: %v", err)
+		}
+		defer srv.Close()
+
+		c := a2s.NewClient(time.Second)
+
+		gotInfo, err := c.Info(context.Background(), srv.Addr())
+		if err != nil {
+			t.Fatalf("Info (challenge=%v): %v", challenge, err)
+		}
+
+		if gotInfo.Map != info.Map || gotInfo.Players != info.Players {
+			t.Errorf("Info (challenge=%v): got %+v", challenge, gotInfo)
+		}
+
+		gotPlayers, err := c.Players(context.Background(), srv.Addr())
+		if err != nil {
+			t.Fatalf("Players (challenge=%v): %v", challenge, err)
+		}
+
+		if len(gotPlayers) != 1 || gotPlayers[0].Name != "player" {
+			t.Errorf("Players (challenge=%v): got %+v", challenge, gotPlayers)
+		}
+	}
+}
+
+func TestClientTimeout(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket: %v", err)
+	}
+	defer conn.Close()
+
+	c := a2s.NewClient(50 * time.Millisecond)
+
+	if _, err := c.Info(context.Background(), conn.LocalAddr().String()); err == nil {
+		t.Fatal("expected timeout error")
+	}
+}
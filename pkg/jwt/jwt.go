@@ -1,10 +1,12 @@
 package jwt
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	m "github.com/cs2-server/backend/internal/model"
@@ -15,30 +17,88 @@ import (
 
 var (
 	ErrTokenExpired = errors.New("token has expired")
+	ErrTokenMissing = errors.New("authorization token is missing")
+	ErrTokenFormat  = errors.New("invalid token format")
+	ErrTokenType    = errors.New("wrong token type")
 )
 
+type ctxKey struct{}
+
+// FailureRecorder is told why a token was rejected: missing, format, type,
+// expired, signature, malformed or invalid.
+type FailureRecorder interface {
+	JWTFailure(reason string)
+}
+
 type JWT struct {
-	key string
+	keys     atomic.Pointer[keys]
+	failures FailureRecorder
+}
+
+type keys struct {
+	sign   []byte
+	verify [][]byte
+}
+
+// New returns JWT signing and verifying with key and also verifying with the
+// previous keys. failures may be nil.
+func New(key string, previous []string, failures FailureRecorder) *JWT {
+	t := &JWT{
+		failures: failures,
+	}
+
+	t.SetKeys(key, previous)
+
+	return t
 }
 
-func New(key string) *JWT {
-	return &JWT{
-		key: key,
+// SetKeys replaces the keys, for tokens issued and verified from then on.
+func (t *JWT) SetKeys(key string, previous []string) {
+	k := &keys{
+		sign:   []byte(key),
+		verify: [][]byte{[]byte(key)},
 	}
+
+	for _, p := range previous {
+		k.verify = append(k.verify, []byte(p))
+	}
+
+	t.keys.Store(k)
 }
 
+// Auth lets the request through only with a valid access token.
 func (t *JWT) Auth(next http.HandlerFunc) http.HandlerFunc {
+	return t.auth(getTokenFromHeader, m.TokenAccess, next)
+}
+
+// AuthQuery is Auth for clients that cannot set headers, such as browser
+// EventSource and WebSocket: the token may also be passed in the
+// access_token query parameter.
+func (t *JWT) AuthQuery(next http.HandlerFunc) http.HandlerFunc {
+	return t.auth(getTokenFromHeaderOrQuery, m.TokenAccess, next)
+}
+
+// Refresh is Auth for the token refresh route, which takes refresh tokens
+// only.
+func (t *JWT) Refresh(next http.HandlerFunc) http.HandlerFunc {
+	return t.auth(getTokenFromHeader, m.TokenRefresh, next)
+}
+
+func (t *JWT) auth(getToken func(*http.Request) (string, error), typ string, next http.HandlerFunc) http.HandlerFunc {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		tokenString, err := getTokenFromHeader(r)
+		tokenString, err := getToken(r)
 		if err != nil {
-			logrus.Errorln("JWT (1):", err)
+			logrus.WithContext(r.Context()).Errorln("JWT (1):", err)
+			t.recordFailure(err)
 			render.Error(w, http.StatusUnauthorized, err.Error())
 
 			return
 		}
 
-		if err := t.verifyToken(tokenString); err != nil {
-			logrus.Errorln("JWT (2): ", err)
+		claims, err := t.verifyToken(tokenString)
+		if err != nil {
+			logrus.WithContext(r.Context()).Errorln("JWT (2): ", err)
+			t.recordFailure(err)
 
 			if errors.Is(err, ErrTokenExpired) {
 				render.Error(w, http.StatusUnauthorized, err.Error(), render.ExpiredToken)
@@ -51,18 +111,43 @@ func (t *JWT) Auth(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		// Tokens issued before types were added carry none and pass as
+		// access tokens until they expire.
+		got := claims.Type
+		if got == "" {
+			got = m.TokenAccess
+		}
+
+		if got != typ {
+			err := fmt.Errorf("JWT (3): %s token: %w", got, ErrTokenType)
+			logrus.WithContext(r.Context()).Errorln(err)
+			t.recordFailure(err)
+			render.Error(w, http.StatusUnauthorized, ErrTokenType.Error())
+
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, claims.ID)))
 	})
 }
 
+// SteamID returns the ID of the player authenticated by Auth.
+func SteamID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(ctxKey{}).(string)
+
+	return id, ok && id != ""
+}
+
 func (t *JWT) GenerateTokens(id string) (m.JWT, error) {
 	var (
+		key            = t.keys.Load().sign
 		accessExpTime  = time.Now().Add(24 * time.Hour)
 		refreshExpTime = time.Now().AddDate(0, 1, 0)
 	)
 
 	accessClaims := &m.JWTClaims{
-		ID: id,
+		ID:   id,
+		Type: m.TokenAccess,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: accessExpTime.Unix(),
 		},
@@ -70,13 +155,14 @@ func (t *JWT) GenerateTokens(id string) (m.JWT, error) {
 
 	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
 
-	signedAccessToken, err := accessToken.SignedString([]byte(t.key))
+	signedAccessToken, err := accessToken.SignedString(key)
 	if err != nil {
 		return m.JWT{}, fmt.Errorf("GenerateToken (1): %w", err)
 	}
 
 	refreshClaims := &m.JWTClaims{
-		ID: id,
+		ID:   id,
+		Type: m.TokenRefresh,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: refreshExpTime.Unix(),
 		},
@@ -84,7 +170,7 @@ func (t *JWT) GenerateTokens(id string) (m.JWT, error) {
 
 	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
 
-	signedRefreshToken, err := refreshToken.SignedString([]byte(t.key))
+	signedRefreshToken, err := refreshToken.SignedString(key)
 	if err != nil {
 		return m.JWT{}, fmt.Errorf("GenerateToken (2): %w", err)
 	}
@@ -98,39 +184,108 @@ func (t *JWT) GenerateTokens(id string) (m.JWT, error) {
 	return tokens, nil
 }
 
-func (t *JWT) verifyToken(signedToken string) error {
-	token, err := jwt.Parse(signedToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+// Inspect returns the claims of token without trusting them, for debugging,
+// along with the reason Auth would reject the token, if any.
+func (t *JWT) Inspect(token string) (*m.JWTClaims, error) {
+	claims := &m.JWTClaims{}
+
+	if _, _, err := new(jwt.Parser).ParseUnverified(token, claims); err != nil {
+		return nil, fmt.Errorf("Inspect: %w", err)
+	}
+
+	_, err := t.verifyToken(token)
+
+	return claims, err
+}
+
+// verifyToken tries each verification key in turn, as long as the signature
+// is what fails.
+func (t *JWT) verifyToken(signedToken string) (*m.JWTClaims, error) {
+	var (
+		claims *m.JWTClaims
+		token  *jwt.Token
+		err    error
+	)
+
+	for _, key := range t.keys.Load().verify {
+		claims = &m.JWTClaims{}
+
+		token, err = jwt.ParseWithClaims(signedToken, claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return key, nil
+		})
+
+		var ve *jwt.ValidationError
+		if !errors.As(err, &ve) || ve.Errors&jwt.ValidationErrorSignatureInvalid == 0 {
+			break
 		}
-		return []byte(t.key), nil
-	})
+	}
+
 	if err != nil {
 		if ve, ok := err.(*jwt.ValidationError); ok {
 			if ve.Errors&jwt.ValidationErrorExpired != 0 {
-				return fmt.Errorf("VerifyToken (1): %w", ErrTokenExpired)
+				return nil, fmt.Errorf("VerifyToken (1): %w", ErrTokenExpired)
 			}
 		}
-		return fmt.Errorf("VerifyToken (2): %w", err)
+		return nil, fmt.Errorf("VerifyToken (2): %w", err)
 	}
 
 	if !token.Valid {
-		return errors.New("VerifyToken (3): invalid token")
+		return nil, errors.New("VerifyToken (3): invalid token")
 	}
 
-	return nil
+	return claims, nil
+}
+
+func (t *JWT) recordFailure(err error) {
+	if t.failures == nil {
+		return
+	}
+
+	t.failures.JWTFailure(failureReason(err))
+}
+
+func failureReason(err error) string {
+	var ve *jwt.ValidationError
+
+	switch {
+	case errors.Is(err, ErrTokenMissing):
+		return "missing"
+	case errors.Is(err, ErrTokenFormat):
+		return "format"
+	case errors.Is(err, ErrTokenType):
+		return "type"
+	case errors.Is(err, ErrTokenExpired):
+		return "expired"
+	case errors.As(err, &ve) && ve.Errors&jwt.ValidationErrorSignatureInvalid != 0:
+		return "signature"
+	case errors.As(err, &ve) && ve.Errors&jwt.ValidationErrorMalformed != 0:
+		return "malformed"
+	default:
+		return "invalid"
+	}
 }
 
 func getTokenFromHeader(r *http.Request) (string, error) {
 	authHeader := r.Header.Get("Authorization")
 	if authHeader == "" {
-		return "", errors.New("getTokenFromHeader (1): authorization token is missing")
+		return "", fmt.Errorf("getTokenFromHeader (1): %w", ErrTokenMissing)
 	}
 
 	tokenParts := strings.Split(authHeader, " ")
 	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-		return "", errors.New("getTokenFromHeader (2): invalid token format")
+		return "", fmt.Errorf("getTokenFromHeader (2): %w", ErrTokenFormat)
 	}
 
 	return tokenParts[1], nil
 }
+
+func getTokenFromHeaderOrQuery(r *http.Request) (string, error) {
+	if token := r.URL.Query().Get("access_token"); token != "" && r.Header.Get("Authorization") == "" {
+		return token, nil
+	}
+
+	return getTokenFromHeader(r)
+}
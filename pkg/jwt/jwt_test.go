@@ -0,0 +1,97 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	m "github.com/cs2-server/backend/internal/model"
+	"github.com/dgrijalva/jwt-go"
+)
+
+func TestSetKeys(t *testing.T) {
+	tokens := New("old-key", nil, nil)
+
+	old, err := tokens.GenerateTokens("76561198000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tokens.SetKeys("new-key", []string{"old-key"})
+
+	current, err := tokens.GenerateTokens("76561198000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verify := func(token string) int {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+
+		tokens.Auth(func(w http.ResponseWriter, r *http.Request) {})(w, r)
+
+		return w.Code
+	}
+
+	if code := verify(old.AccessToken); code != http.StatusOK {
+		t.Errorf("token of the previous key: %d", code)
+	}
+
+	if code := verify(current.AccessToken); code != http.StatusOK {
+		t.Errorf("token of the new key: %d", code)
+	}
+
+	tokens.SetKeys("new-key", nil)
+
+	if code := verify(old.AccessToken); code != http.StatusUnauthorized {
+		t.Errorf("token of a retired key: %d", code)
+	}
+}
+
+func TestTokenTypes(t *testing.T) {
+	const key = "token-types-key-of-at-least-32-bytes"
+
+	tokens := New(key, nil, nil)
+
+	pair, err := tokens.GenerateTokens("76561198000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &m.JWTClaims{
+		ID:             "76561198000000001",
+		StandardClaims: jwt.StandardClaims{ExpiresAt: time.Now().Add(time.Hour).Unix()},
+	}).SignedString([]byte(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		mw    func(http.HandlerFunc) http.HandlerFunc
+		token string
+		want  int
+	}{
+		{"access token on Auth", tokens.Auth, pair.AccessToken, http.StatusOK},
+		{"refresh token on Auth", tokens.Auth, pair.RefreshToken, http.StatusUnauthorized},
+		{"refresh token on AuthQuery", tokens.AuthQuery, pair.RefreshToken, http.StatusUnauthorized},
+		{"refresh token on Refresh", tokens.Refresh, pair.RefreshToken, http.StatusOK},
+		{"access token on Refresh", tokens.Refresh, pair.AccessToken, http.StatusUnauthorized},
+		{"untyped token on Auth", tokens.Auth, legacy, http.StatusOK},
+		{"untyped token on Refresh", tokens.Refresh, legacy, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set("Authorization", "Bearer "+tt.token)
+
+		tt.mw(func(w http.ResponseWriter, r *http.Request) {})(w, r)
+
+		if w.Code != tt.want {
+			t.Errorf("%s: got %d, want %d", tt.name, w.Code, tt.want)
+		}
+	}
+}
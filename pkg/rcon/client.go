@@ -0,0 +1,223 @@
+package rcon
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrAuthFailed = errors.New("rcon authentication failed")
+)
+
+// Client keeps a single authenticated connection to a server and reconnects
+// whenever it breaks. It is safe for concurrent use, commands are executed
+// one at a time.
+type Client struct {
+	addr     string
+	password string
+	timeout  time.Duration
+
+	mu     sync.Mutex
+	conn   net.Conn
+	reader *bufio.Reader
+	id     int32
+}
+
+func NewClient(addr, password string, timeout time.Duration) *Client {
+	return &Client{
+		addr:     addr,
+		password: password,
+		timeout:  timeout,
+	}
+}
+
+// Execute runs command and returns its output. Responses split across
+// several packets are joined. A connection the server has closed since the
+// last command is re-established first. Once command is written it is never
+// sent again, as it may have run already.
+func (c *Client) Execute(ctx context.Context, command string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reused := c.conn != nil
+
+	out, sent, err := c.execute(ctx, command)
+	if err == nil {
+		return out, nil
+	}
+
+	c.close()
+
+	// Only a kept connection that broke before the command got out is worth
+	// another try.
+	if !reused || sent || ctx.Err() != nil {
+		return "", fmt.Errorf("Execute (1): %w", err)
+	}
+
+	out, _, err = c.execute(ctx, command)
+	if err != nil {
+		c.close()
+
+		return "", fmt.Errorf("Execute (2): %w", err)
+	}
+
+	return out, nil
+}
+
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.close()
+}
+
+// execute reports whether command was written, after which it must not be
+// retried.
+func (c *Client) execute(ctx context.Context, command string) (string, bool, error) {
+	if c.conn != nil && !c.alive() {
+		c.close()
+	}
+
+	if c.conn == nil {
+		if err := c.connect(ctx); err != nil {
+			return "", false, err
+		}
+	}
+
+	if err := c.setDeadline(ctx); err != nil {
+		return "", false, err
+	}
+
+	cmdID, mirrorID := c.nextID(), c.nextID()
+
+	if err := WritePacket(c.conn, Packet{ID: cmdID, Type: TypeExecCommand, Body: command}); err != nil {
+		return "", false, fmt.Errorf("execute (1): %w", err)
+	}
+
+	// The server answers packets in order, so the response to this empty
+	// packet marks the end of the command output.
+	if err := WritePacket(c.conn, Packet{ID: mirrorID, Type: TypeResponseValue}); err != nil {
+		return "", true, fmt.Errorf("execute (2): %w", err)
+	}
+
+	var out strings.Builder
+
+	for {
+		p, err := ReadPacket(c.reader)
+		if err != nil {
+			return "", true, fmt.Errorf("execute (3): %w", err)
+		}
+
+		switch p.ID {
+		case cmdID:
+			out.WriteString(p.Body)
+		case mirrorID:
+			return out.String(), true, nil
+		}
+	}
+}
+
+// alive reports whether a kept connection can take a command. Between
+// commands the server sends nothing, so a connection it has closed reads EOF
+// at once while a live one times out. The deadline lies ahead, a past one
+// would time out without reading at all.
+func (c *Client) alive() bool {
+	if err := c.conn.SetReadDeadline(time.Now().Add(time.Millisecond)); err != nil {
+		return false
+	}
+
+	_, err := c.reader.Peek(1)
+
+	var netErr net.Error
+
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func (c *Client) connect(ctx context.Context) error {
+	d := net.Dialer{Timeout: c.timeout}
+
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return fmt.Errorf("connect (1): %w", err)
+	}
+
+	c.conn = conn
+	c.reader = bufio.NewReader(conn)
+
+	if err := c.setDeadline(ctx); err != nil {
+		c.close()
+
+		return err
+	}
+
+	id := c.nextID()
+
+	if err := WritePacket(c.conn, Packet{ID: id, Type: TypeAuth, Body: c.password}); err != nil {
+		c.close()
+
+		return fmt.Errorf("connect (2): %w", err)
+	}
+
+	for {
+		p, err := ReadPacket(c.reader)
+		if err != nil {
+			c.close()
+
+			return fmt.Errorf("connect (3): %w", err)
+		}
+
+		// Source servers send an empty response value before the auth
+		// response, skip it.
+		if p.Type != TypeAuthResponse {
+			continue
+		}
+
+		if p.ID != id {
+			c.close()
+
+			return fmt.Errorf("connect (4): %w", ErrAuthFailed)
+		}
+
+		return nil
+	}
+}
+
+func (c *Client) setDeadline(ctx context.Context) error {
+	deadline := time.Now().Add(c.timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	if err := c.conn.SetDeadline(deadline); err != nil {
+		return fmt.Errorf("setDeadline: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) nextID() int32 {
+	c.id++
+	if c.id <= 0 {
+		c.id = 1
+	}
+
+	return c.id
+}
+
+func (c *Client) close() error {
+	if c.conn == nil {
+		return nil
+	}
+
+	err := c.conn.Close()
+	c.conn = nil
+	c.reader = nil
+
+	return err
+}
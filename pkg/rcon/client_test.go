@@ -0,0 +1,114 @@
+package rcon_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cs2-server/backend/pkg/rcon"
+	"github.com/cs2-server/backend/pkg/rcon/rcontest"
+)
+
+func TestClientExecute(t *testing.T) {
+	srv, err := rcontest.NewServer("secret", func(command string) string {
+		return strings.Repeat(command+";", 20)
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	defer srv.Close()
+
+	c := rcon.NewClient(srv.Addr(), "secret", time.Second)
+	defer c.Close()
+
+	for _, command := range []string{"status", "mp_restartgame 1"} {
+		out, err := c.Execute(context.Background(), command)
+		if err != nil {
+			t.Fatalf("Execute %q: %v", command, err)
+		}
+
+		if want := strings.Repeat(command+";", 20); out != want {
+			t.Errorf("Execute %q: got %q, want %q", command, out, want)
+		}
+	}
+}
+
+func TestClientReconnect(t *testing.T) {
+	srv, err := rcontest.NewServer("secret", func(command string) string {
+		return "ok"
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	defer srv.Close()
+
+	c := rcon.NewClient(srv.Addr(), "secret", time.Second)
+	defer c.Close()
+
+	if _, err := c.Execute(context.Background(), "status"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	srv.DropConnections()
+
+	out, err := c.Execute(context.Background(), "status")
+	if err != nil {
+		t.Fatalf("Execute after drop: %v", err)
+	}
+
+	if out != "ok" {
+		t.Errorf("got %q, want %q", out, "ok")
+	}
+}
+
+func TestClientNoResend(t *testing.T) {
+	var calls atomic.Int32
+
+	srv, err := rcontest.NewServer("secret", func(command string) string {
+		if calls.Add(1) > 1 {
+			time.Sleep(300 * time.Millisecond)
+		}
+
+		return "ok"
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	defer srv.Close()
+
+	c := rcon.NewClient(srv.Addr(), "secret", 100*time.Millisecond)
+	defer c.Close()
+
+	if _, err := c.Execute(context.Background(), "status"); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+
+	// The kick reaches the server but its answer times out.
+	if _, err := c.Execute(context.Background(), "kick player"); err == nil {
+		t.Fatal("Execute succeeded past the timeout")
+	}
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("server ran %d commands, want 2", n)
+	}
+}
+
+func TestClientAuthFailed(t *testing.T) {
+	srv, err := rcontest.NewServer("secret", func(command string) string {
+		return ""
+	})
+	if err != nil {
+		t.Fatalf("NewServer: %v", err)
+	}
+	defer srv.Close()
+
+	c := rcon.NewClient(srv.Addr(), "wrong", time.Second)
+	defer c.Close()
+
+	if _, err := c.Execute(context.Background(), "status"); !errors.Is(err, rcon.ErrAuthFailed) {
+		t.Fatalf("got %v, want %v", err, rcon.ErrAuthFailed)
+	}
+}
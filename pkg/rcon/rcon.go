@@ -0,0 +1,83 @@
+package rcon
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	TypeResponseValue int32 = 0
+	TypeExecCommand   int32 = 2
+	TypeAuthResponse  int32 = 2
+	TypeAuth          int32 = 3
+
+	// headerSize is the size of the id and type fields, the body terminator
+	// and the trailing empty string.
+	headerSize = 10
+
+	maxPacketSize = 4096 + headerSize
+)
+
+var (
+	ErrPacketTooLarge = errors.New("packet is too large")
+	ErrMalformed      = errors.New("malformed packet")
+)
+
+type Packet struct {
+	ID   int32
+	Type int32
+	Body string
+}
+
+// WritePacket writes p to w in the Source RCON wire format.
+func WritePacket(w io.Writer, p Packet) error {
+	size := len(p.Body) + headerSize
+	if size > maxPacketSize {
+		return fmt.Errorf("WritePacket (1): %w", ErrPacketTooLarge)
+	}
+
+	b := make([]byte, 0, size+4)
+	b = binary.LittleEndian.AppendUint32(b, uint32(size))
+	b = binary.LittleEndian.AppendUint32(b, uint32(p.ID))
+	b = binary.LittleEndian.AppendUint32(b, uint32(p.Type))
+	b = append(b, p.Body...)
+	b = append(b, 0, 0)
+
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("WritePacket (2): %w", err)
+	}
+
+	return nil
+}
+
+// ReadPacket reads a single packet from r.
+func ReadPacket(r *bufio.Reader) (Packet, error) {
+	var size int32
+	if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+		return Packet{}, fmt.Errorf("ReadPacket (1): %w", err)
+	}
+
+	if size < headerSize || size > maxPacketSize {
+		return Packet{}, fmt.Errorf("ReadPacket (2): %w", ErrMalformed)
+	}
+
+	b := make([]byte, size)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return Packet{}, fmt.Errorf("ReadPacket (3): %w", err)
+	}
+
+	body := b[8 : size-2]
+	if i := bytes.IndexByte(body, 0); i >= 0 {
+		body = body[:i]
+	}
+
+	return Packet{
+		ID:   int32(binary.LittleEndian.Uint32(b[0:])),
+		Type: int32(binary.LittleEndian.Uint32(b[4:])),
+		Body: string(body),
+	}, nil
+}
@@ -0,0 +1,137 @@
+// Package rcontest provides a local TCP server that speaks Source RCON, for
+// use in tests.
+package rcontest
+
+import (
+	"bufio"
+	"net"
+	"sync"
+
+	"github.com/cs2-server/backend/pkg/rcon"
+)
+
+// chunkSize is the size of the body of a single response packet. It is much
+// smaller than on real servers so tests hit multi-packet responses easily.
+const chunkSize = 16
+
+type Handler func(command string) string
+
+type Server struct {
+	listener net.Listener
+	password string
+	handler  Handler
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+
+	wg sync.WaitGroup
+}
+
+// NewServer starts a server on a random local port that answers every command
+// with the output of handler.
+func NewServer(password string, handler Handler) (*Server, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		listener: l,
+		password: password,
+		handler:  handler,
+		conns:    make(map[net.Conn]struct{}),
+	}
+
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// DropConnections closes every open client connection while keeping the
+// server running.
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *Server) Close() error {
+	err := s.listener.Close()
+	s.DropConnections()
+	s.wg.Wait()
+
+	return err
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	authed := false
+
+	for {
+		p, err := rcon.ReadPacket(r)
+		if err != nil {
+			return
+		}
+
+		switch {
+		case p.Type == rcon.TypeAuth:
+			id := p.ID
+			if p.Body != s.password {
+				id = -1
+			} else {
+				authed = true
+			}
+
+			rcon.WritePacket(conn, rcon.Packet{ID: p.ID, Type: rcon.TypeResponseValue})
+			rcon.WritePacket(conn, rcon.Packet{ID: id, Type: rcon.TypeAuthResponse})
+		case !authed:
+			return
+		case p.Type == rcon.TypeExecCommand:
+			out := s.handler(p.Body)
+
+			for len(out) > chunkSize {
+				rcon.WritePacket(conn, rcon.Packet{ID: p.ID, Type: rcon.TypeResponseValue, Body: out[:chunkSize]})
+				out = out[chunkSize:]
+			}
+
+			rcon.WritePacket(conn, rcon.Packet{ID: p.ID, Type: rcon.TypeResponseValue, Body: out})
+		case p.Type == rcon.TypeResponseValue:
+			rcon.WritePacket(conn, rcon.Packet{ID: p.ID, Type: rcon.TypeResponseValue})
+		}
+	}
+}